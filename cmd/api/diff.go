@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"timetabling-UDP/internal/diff"
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// runDiff implementa el subcomando diff: compara dos schedule.json y reporta las actividades que cambiaron de
+// horario, sala o profesor, agrupadas por curso, profesor y sala
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	from := fs.String("from", "", "horario anterior (JSON)")
+	to := fs.String("to", "data/output/schedule.json", "horario nuevo (JSON)")
+	gridPath := fs.String("grid", "data/input/time_grid.json", "grilla semanal y periodos bloqueados (JSON)")
+	verbose := fs.Bool("v", false, "detalla cada cambio también en los grupos por profesor y por sala")
+	fs.Parse(args)
+
+	if *from == "" {
+		log.Fatalf("Error: falta -from con el horario anterior")
+	}
+
+	grid, err := loader.LoadTimeGrid(*gridPath)
+	if err != nil {
+		log.Fatalf("Error cargando grilla horaria: %v", err)
+	}
+	domain.SetTimeGrid(grid)
+
+	before, err := loader.ImportScheduleFromJSON(*from)
+	if err != nil {
+		log.Fatalf("Error leyendo horario %s: %v", *from, err)
+	}
+	after, err := loader.ImportScheduleFromJSON(*to)
+	if err != nil {
+		log.Fatalf("Error leyendo horario %s: %v", *to, err)
+	}
+
+	fmt.Printf("Horario anterior: %s (%d actividades)\n", *from, len(before))
+	fmt.Printf("Horario nuevo:    %s (%d actividades)\n", *to, len(after))
+	printDiff(diff.Compare(before, after), *verbose)
+}
+
+// printDiff imprime el resumen de un diff y los cambios por curso; por profesor y por sala se listan los códigos
+// afectados (con verbose, cada cambio)
+func printDiff(report diff.Report, verbose bool) {
+	n := report.Counts()
+	fmt.Printf("\n Cambios:\n")
+	fmt.Printf("   Sin cambios:        %d\n", report.Unchanged)
+	fmt.Printf("   Con cambios:        %d (horario %d, sala %d, profesores %d)\n", n.Changed, n.Block, n.Room, n.Teachers)
+	fmt.Printf("   Nuevas:             %d\n", n.Added)
+	fmt.Printf("   Eliminadas:         %d\n", n.Removed)
+	if len(report.Changes) == 0 {
+		return
+	}
+
+	courses := report.ByCourse()
+	fmt.Printf("\n Por curso (%d):\n", len(courses))
+	for _, g := range courses {
+		fmt.Printf("   %s (%d)\n", g.Name, len(g.Changes))
+		for _, c := range g.Changes {
+			fmt.Printf("      - %s\n", c)
+		}
+	}
+
+	for _, section := range []struct {
+		title  string
+		groups []diff.Group
+	}{
+		{"Por profesor", report.ByTeacher()},
+		{"Por sala", report.ByRoom()},
+	} {
+		fmt.Printf("\n %s (%d):\n", section.title, len(section.groups))
+		for _, g := range section.groups {
+			if verbose {
+				fmt.Printf("   %s (%d)\n", g.Name, len(g.Changes))
+				for _, c := range g.Changes {
+					fmt.Printf("      - %s\n", c)
+				}
+				continue
+			}
+			codes := make([]string, len(g.Changes))
+			for i, c := range g.Changes {
+				codes[i] = c.Code
+			}
+			fmt.Printf("   %s (%d): %s\n", g.Name, len(g.Changes), joinLimited(codes, 6))
+		}
+	}
+}
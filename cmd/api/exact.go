@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/graph"
+	"timetabling-UDP/internal/solver"
+)
+
+// runExact implementa el subcomando exact: formulación entera de una instancia pequeña (un semestre, un grupo de
+// cursos), exportable a LP/MPS, resuelta con branch-and-bound y comparada con el resultado de SA
+func runExact(args []string) {
+	saDefaults := solver.DefaultSAConfig()
+	exactDefaults := solver.DefaultExactConfig()
+
+	fs := flag.NewFlagSet("exact", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	major := fs.String("major", "", "solo cursos de esta carrera (según PlanLocation)")
+	semester := fs.Int("semester", 0, "solo cursos de este semestre (0 = todos)")
+	courses := fs.String("course-codes", "", "cursos a incluir, separados por coma (además de -major/-semester)")
+	maxActivities := fs.Int("max-activities", 40, "tamaño máximo de la instancia para branch-and-bound (0 = sin límite)")
+	lpPath := fs.String("lp", "", "escribe el modelo en formato LP")
+	mpsPath := fs.String("mps", "", "escribe el modelo en formato MPS")
+	nodes := fs.Int("nodes", exactDefaults.NodeLimit, "nodos máximos de branch-and-bound (0 = sin límite)")
+	timeLimit := fs.Duration("time", exactDefaults.TimeLimit, "tiempo máximo de branch-and-bound (0 = sin límite)")
+	weightsPath := fs.String("weights", "data/input/objective_weights.json", "pesos de la función objetivo (JSON, vacío = por defecto)")
+	seed := fs.Int64("seed", 1, "semilla del scheduler y de SA")
+	iterations := fs.Int("iterations", 1000, "iteraciones por nivel de temperatura de SA")
+	coolingRate := fs.Float64("cooling", 0.99, "tasa de enfriamiento de SA")
+	output := fs.String("out", "", "exporta el mejor horario de la instancia (JSON)")
+	allowPartial := fs.Bool("allow-partial", false, "con -out, exporta el horario aunque el validador encuentre violaciones duras")
+	verbose := fs.Bool("v", false, "lista las violaciones del validador, no solo el conteo por regla")
+	fs.Parse(args)
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	weights, err := loadWeights(*weightsPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	activities := selectInstance(pr, *major, *semester, *courses)
+	if len(activities) == 0 {
+		log.Fatalf("Error: ninguna actividad cumple el filtro (usar -major, -semester o -course-codes)")
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("           MODELO EXACTO")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+
+	model := solver.BuildExactModel(activities, pr.rooms, weights, pr.prerequisites, pr.planLocations, pr.electives, pr.roomConstraints, pr.teachers)
+	fmt.Printf("\n  Instancia:\n")
+	fmt.Printf("   Actividades:  %d\n", model.NumActivities())
+	fmt.Printf("   Variables x:  %d\n", model.NumVars())
+	fmt.Printf("   Cliques:      %d (+ %d aristas sueltas)\n", model.NumCliques(), model.NumEdges())
+
+	writeModel := func(path string, write func(*os.File) error) {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("Error creando %s: %v", path, err)
+		}
+		defer f.Close()
+		if err := write(f); err != nil {
+			log.Fatalf("Error escribiendo %s: %v", path, err)
+		}
+		fmt.Printf("   Modelo escrito en: %s\n", path)
+	}
+	if *lpPath != "" {
+		writeModel(*lpPath, func(f *os.File) error { return model.WriteLP(f) })
+	}
+	if *mpsPath != "" {
+		writeModel(*mpsPath, func(f *os.File) error { return model.WriteMPS(f) })
+	}
+
+	if *maxActivities > 0 && len(activities) > *maxActivities {
+		fmt.Printf("\n Instancia de %d actividades: supera -max-activities %d, no se ejecuta branch-and-bound\n", len(activities), *maxActivities)
+		return
+	}
+
+	// Heurística sobre la misma instancia: scheduler + SA
+	conflictGraph := graph.BuildFromActivitiesWithCliques(activities, pr.planLocations, pr.electives)
+	solver.IntegratedSchedulerWithConstraints(activities, conflictGraph, pr.rooms, pr.roomConstraints, pr.teachers, pr.planLocations, nil, rand.New(rand.NewSource(*seed)))
+
+	config := saDefaults
+	config.IterationsPerT = *iterations
+	config.CoolingRate = *coolingRate
+	config.Seed = *seed
+	config.Weights = weights
+	saResult := solver.SimulatedAnnealing(activities, pr.rooms, config, pr.prerequisites, pr.planLocations, pr.electives, pr.roomConstraints, pr.teachers)
+
+	fmt.Printf("\n Resultado SA:\n")
+	fmt.Printf("   Costo final:        %.0f\n", saResult.FinalCost)
+	if len(saResult.HardViolations) > 0 {
+		fmt.Printf("   HORARIO PARCIAL: %d violaciones duras pendientes\n", len(saResult.HardViolations))
+	}
+
+	// Branch-and-bound, partiendo del horario de SA si es factible
+	fmt.Println("\n Ejecutando branch-and-bound...")
+	result := solver.SolveExact(model, solver.ExactConfig{NodeLimit: *nodes, TimeLimit: *timeLimit})
+
+	fmt.Printf("\n Resultado exacto:\n")
+	switch {
+	case result.Optimal && !result.Feasible:
+		fmt.Println("   INFACTIBLE: ningún horario cumple las restricciones duras")
+	case result.Optimal:
+		fmt.Println("   ÓPTIMO")
+	default:
+		fmt.Println("   LÍMITE: búsqueda detenida por -nodes o -time")
+	}
+	if result.Feasible {
+		fmt.Printf("   Mejor costo:        %.0f\n", result.Cost)
+	}
+	if !math.IsInf(result.LowerBound, 0) {
+		fmt.Printf("   Cota inferior:      %.0f\n", result.LowerBound)
+	}
+	fmt.Printf("   Nodos:              %d\n", result.Nodes)
+	fmt.Printf("   Tiempo:             %s\n", result.Elapsed.Round(time.Millisecond))
+
+	fmt.Printf("\n Brecha de SA:\n")
+	switch {
+	case math.IsNaN(result.StartCost):
+		fmt.Println("   El horario de SA no es factible para el modelo")
+	case math.IsInf(result.LowerBound, -1):
+		fmt.Println("   Sin cota inferior (la búsqueda no alcanzó a comenzar)")
+	default:
+		fmt.Printf("   Costo SA:           %.0f\n", result.StartCost)
+		fmt.Printf("   Brecha de optimalidad: %.1f%%\n", result.Gap(result.StartCost))
+		if result.Feasible && result.Cost < result.StartCost {
+			fmt.Printf("   Branch-and-bound mejoró a SA en %.0f\n", result.StartCost-result.Cost)
+		}
+	}
+
+	if *output != "" {
+		violations := validateSchedule(pr, activities, *verbose)
+		satisfaction := domain.CalculateTeacherSatisfaction(activities, pr.teachers)
+		exportIfValid(activities, satisfaction, violations, *output, *allowPartial)
+	}
+}
+
+// selectInstance retorna una copia de las actividades de los cursos que cumplen el filtro, siempre con todas
+// las actividades de cada curso
+func selectInstance(pr *problem, major string, semester int, courseCodes string) []domain.Activity {
+	include := make(map[string]bool)
+	for _, code := range strings.Split(courseCodes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			include[code] = true
+		}
+	}
+	if major != "" || semester > 0 {
+		for code, locations := range pr.planLocations {
+			for m, s := range locations {
+				if (major == "" || m == major) && (semester == 0 || s == semester) {
+					include[code] = true
+				}
+			}
+		}
+	}
+
+	var selected []domain.Activity
+	for _, a := range pr.activities {
+		if include[a.CourseCode] {
+			selected = append(selected, a)
+		}
+	}
+	return selected
+}
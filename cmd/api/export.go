@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/exporter"
+	"timetabling-UDP/internal/loader"
+)
+
+// runExport implementa el subcomando export: lee un schedule.json y lo vuelve a exportar
+// (p.ej. con otra grilla o para recalcular el resumen) y genera las planillas CSV/XLSX, los calendarios iCalendar y las grillas HTML para imprimir
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	input := fs.String("in", "data/output/schedule.json", "horario existente (JSON)")
+	output := fs.String("out", "data/output/schedule.json", "archivo de salida (JSON)")
+	gridPath := fs.String("grid", "data/input/time_grid.json", "grilla semanal y periodos bloqueados (JSON)")
+	teachersPath := fs.String("teachers", "data/input/profesores.json", "profesores, para la satisfacción de preferencias (JSON)")
+	icsDir := fs.String("ics", "", "directorio donde escribir los calendarios .ics (vacío = no generar)")
+	calendarPath := fs.String("calendar", "data/input/semester_calendar.json", "fechas del semestre y feriados, para -ics (JSON)")
+	coursesPath := fs.String("courses", "data/input/courses.json", "cursos con PlanLocation, para los calendarios y grillas por carrera (JSON)")
+	csvPath := fs.String("csv", "", "planilla CSV con una fila por sesión (vacío = no generar)")
+	xlsxPath := fs.String("xlsx", "", "planilla XLSX con una fila por sesión (vacío = no generar)")
+	columnsPath := fs.String("columns", "", "mapeo de columnas de las planillas (JSON, vacío = columnas por defecto)")
+	htmlDir := fs.String("html", "", "directorio donde escribir las grillas HTML por sala, profesor y carrera-semestre (vacío = no generar)")
+	roomsPath := fs.String("rooms", "data/input/rooms.csv", "salas, para las grillas HTML (CSV)")
+	fs.Parse(args)
+
+	// Con -ics, -csv, -xlsx o -html el JSON solo se reescribe si se indicó -out explícitamente
+	writeJSON := *icsDir == "" && *csvPath == "" && *xlsxPath == "" && *htmlDir == ""
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "out" {
+			writeJSON = true
+		}
+	})
+
+	grid, err := loader.LoadTimeGrid(*gridPath)
+	if err != nil {
+		log.Fatalf("Error cargando grilla horaria: %v", err)
+	}
+	domain.SetTimeGrid(grid)
+
+	teachers, err := loader.LoadTeachers(*teachersPath)
+	if err != nil {
+		log.Fatalf("Error cargando profesores: %v", err)
+	}
+
+	activities, err := loader.ImportScheduleFromJSON(*input)
+	if err != nil {
+		log.Fatalf("Error leyendo horario: %v", err)
+	}
+
+	if writeJSON {
+		satisfaction := domain.CalculateTeacherSatisfaction(activities, teachers)
+		if err := exporter.ExportScheduleToJSON(activities, satisfaction, *output); err != nil {
+			log.Fatalf("Error exportando JSON: %v", err)
+		}
+		fmt.Printf("Horario exportado a: %s (%d actividades)\n", *output, len(activities))
+	}
+
+	if *csvPath != "" || *xlsxPath != "" {
+		mapping := exporter.DefaultColumnMapping()
+		if *columnsPath != "" {
+			if mapping, err = exporter.LoadColumnMapping(*columnsPath); err != nil {
+				log.Fatalf("Error cargando mapeo de columnas: %v", err)
+			}
+		}
+		if *csvPath != "" {
+			rows, skipped, err := exporter.ExportScheduleToCSV(activities, mapping, *csvPath)
+			if err != nil {
+				log.Fatalf("Error exportando CSV: %v", err)
+			}
+			fmt.Printf("Planilla CSV exportada a: %s (%d sesiones, %d sin programar omitidas)\n", *csvPath, rows, skipped)
+		}
+		if *xlsxPath != "" {
+			rows, skipped, err := exporter.ExportScheduleToXLSX(activities, mapping, *xlsxPath)
+			if err != nil {
+				log.Fatalf("Error exportando XLSX: %v", err)
+			}
+			fmt.Printf("Planilla XLSX exportada a: %s (%d sesiones, %d sin programar omitidas)\n", *xlsxPath, rows, skipped)
+		}
+	}
+
+	var planLocations map[string]map[string]int
+	if *icsDir != "" || *htmlDir != "" {
+		if planLocations, err = loader.LoadCoursePlanLocations(*coursesPath); err != nil {
+			log.Fatalf("Error cargando PlanLocations: %v", err)
+		}
+	}
+
+	if *icsDir != "" {
+		cal, err := loader.LoadSemesterCalendar(*calendarPath)
+		if err != nil {
+			log.Fatalf("Error cargando calendario del semestre: %v", err)
+		}
+		summary, err := exporter.ExportICS(activities, planLocations, cal, *icsDir)
+		if err != nil {
+			log.Fatalf("Error exportando iCalendar: %v", err)
+		}
+		fmt.Printf("Calendarios exportados a: %s (%d profesores, %d salas, %d secciones, %d carrera-semestre)\n",
+			*icsDir, summary.Teachers, summary.Rooms, summary.Sections, summary.Semesters)
+	}
+
+	if *htmlDir != "" {
+		rooms, err := loader.LoadRooms(*roomsPath)
+		if err != nil {
+			log.Fatalf("Error cargando salas: %v", err)
+		}
+		summary, err := exporter.ExportHTML(activities, rooms, teachers, planLocations, *htmlDir)
+		if err != nil {
+			log.Fatalf("Error exportando HTML: %v", err)
+		}
+		fmt.Printf("Grillas HTML exportadas a: %s/index.html (%d salas, %d profesores, %d carrera-semestre)\n",
+			*htmlDir, summary.Rooms, summary.Teachers, summary.Semesters)
+	}
+}
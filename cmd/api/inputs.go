@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// inputPaths agrupa las rutas de los archivos de entrada
+type inputPaths struct {
+	oferta          string
+	courses         string
+	rooms           string
+	teachers        string
+	roomConstraints string
+	grid            string
+}
+
+// addInputFlags registra los flags de archivos de entrada en un subcomando
+func addInputFlags(fs *flag.FlagSet) *inputPaths {
+	p := &inputPaths{}
+	fs.StringVar(&p.oferta, "oferta", "data/input/oferta_academica.json", "oferta académica (JSON)")
+	fs.StringVar(&p.courses, "courses", "data/input/courses.json", "cursos con distribución y PlanLocation (JSON)")
+	fs.StringVar(&p.rooms, "rooms", "data/input/rooms.csv", "salas y capacidades (CSV)")
+	fs.StringVar(&p.teachers, "teachers", "data/input/profesores.json", "profesores y disponibilidad (JSON)")
+	fs.StringVar(&p.roomConstraints, "room-constraints", "data/input/rooms_constraints.json", "restricciones de salas por curso (JSON)")
+	fs.StringVar(&p.grid, "grid", "data/input/time_grid.json", "grilla semanal y periodos bloqueados (JSON)")
+	return p
+}
+
+// validateInputs revisa los archivos de entrada, la grilla activa ya debe estar configurada
+func validateInputs(p *inputPaths) []loader.Issue {
+	return loader.ValidateInputs(loader.InputFiles{
+		Oferta:          p.oferta,
+		Courses:         p.courses,
+		Rooms:           p.rooms,
+		Teachers:        p.teachers,
+		RoomConstraints: p.roomConstraints,
+	})
+}
+
+// loadWeights carga los pesos de la función objetivo, o los por defecto si path está vacío
+func loadWeights(path string) (domain.ObjectiveWeights, error) {
+	if path == "" {
+		return domain.DefaultObjectiveWeights(), nil
+	}
+	weights, err := loader.LoadObjectiveWeights(path)
+	if err != nil {
+		return weights, fmt.Errorf("error cargando pesos %s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// problem contiene todos los datos de entrada cargados
+type problem struct {
+	activities      []domain.Activity
+	rooms           []domain.Room
+	teachers        []domain.Teacher
+	roomConstraints loader.RoomConstraints
+	planLocations   map[string]map[string]int
+	electives       map[string]bool
+	prerequisites   map[string][]string
+}
+
+// loadProblem carga todos los archivos de entrada, la grilla primero porque el resto depende de ella
+func loadProblem(p *inputPaths) (*problem, error) {
+	grid, err := loader.LoadTimeGrid(p.grid)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando grilla horaria: %w", err)
+	}
+	domain.SetTimeGrid(grid)
+
+	pr := &problem{}
+
+	// Cargar actividades según Distribution
+	pr.activities, err = loader.LoadActivitiesWithExpansion(p.oferta, p.courses)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando actividades: %w", err)
+	}
+
+	// Cargar salas desde CSV
+	pr.rooms, err = loader.LoadRooms(p.rooms)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando salas: %w", err)
+	}
+
+	// Cargar profesores desde JSON
+	pr.teachers, err = loader.LoadTeachers(p.teachers)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando profesores: %w", err)
+	}
+
+	// Cargar restricciones de salas
+	pr.roomConstraints, err = loader.LoadRoomConstraints(p.roomConstraints)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando restricciones de salas: %w", err)
+	}
+
+	// Cargar PlanLocations para cliques de semestre
+	pr.planLocations, err = loader.LoadCoursePlanLocations(p.courses)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando plan locations: %w", err)
+	}
+
+	// Cargar cursos electivos
+	pr.electives, err = loader.LoadElectives(p.courses)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando electivos: %w", err)
+	}
+
+	// Cargar prerrequisitos
+	pr.prerequisites, err = loader.LoadPrerequisites(p.courses)
+	if err != nil {
+		return nil, fmt.Errorf("error cargando prerrequisitos: %w", err)
+	}
+
+	return pr, nil
+}
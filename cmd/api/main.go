@@ -2,208 +2,56 @@ package main
 
 import (
 	"fmt"
-	"log"
-
-	"timetabling-UDP/internal/domain"
-	"timetabling-UDP/internal/exporter"
-	"timetabling-UDP/internal/graph"
-	"timetabling-UDP/internal/loader"
-	"timetabling-UDP/internal/solver"
+	"os"
+	"strings"
 )
 
-func main() {
-	// Cargar actividades según Distribution
-	activities, err := loader.LoadActivitiesWithExpansion(
-		"data/input/oferta_academica.json",
-		"data/input/courses.json",
-	)
-	if err != nil {
-		log.Fatalf("Error cargando actividades: %v", err)
-	}
-
-	// Cargar salas desde CSV
-	rooms, err := loader.LoadRooms("data/input/rooms.csv")
-	if err != nil {
-		log.Fatalf("Error cargando salas: %v", err)
-	}
+const usage = `Uso: timetabling <subcomando> [flags]
 
-	// Cargar profesores desde JSON
-	teachers, err := loader.LoadTeachers("data/input/profesores.json")
-	if err != nil {
-		log.Fatalf("Error cargando profesores: %v", err)
-	}
+Subcomandos:
+   solve              genera el horario (scheduler + simulated annealing o búsqueda tabú) y lo exporta a JSON
+   resolve            re-optimiza un horario publicado tras cambios en las entradas, moviendo lo mínimo
+   validate           carga y valida los archivos de entrada
+   validate-schedule  revisa las reglas duras de un schedule.json existente
+   exact              modelo exacto de una instancia pequeña: LP/MPS, branch-and-bound y brecha de SA
+   stats              muestra estadísticas de las entradas y del grafo de conflictos
+   diff               compara dos schedule.json: cambios de horario, sala y profesor por curso, profesor y sala
+   export             re-exporta un schedule.json existente, a planillas CSV/XLSX, calendarios iCalendar y grillas HTML
 
-	// Cargar restricciones de salas
-	roomConstraints, err := loader.LoadRoomConstraints("data/input/rooms_constraints.json")
-	if err != nil {
-		log.Fatalf("Error cargando restricciones de salas: %v", err)
-	}
-
-	// Cargar PlanLocations para cliques de semestre
-	planLocations, err := loader.LoadCoursePlanLocations("data/input/courses.json")
-	if err != nil {
-		log.Fatalf("Error cargando plan locations: %v", err)
-	}
-
-	// Cargar cursos electivos
-	electives, err := loader.LoadElectives("data/input/courses.json")
-	if err != nil {
-		log.Fatalf("Error cargando electivos: %v", err)
-	}
-
-	// Construir grafo de conflictos con cliques de semestre (sin electivos)
-	conflictGraph := graph.BuildFromActivitiesWithCliques(activities, planLocations, electives)
-
-	// Estadísticas generales
-	fmt.Println("═══════════════════════════════════════════════════════════")
-	fmt.Println("           UDP TIMETABLING")
-	fmt.Println("═══════════════════════════════════════════════════════════")
-	fmt.Printf("Total de actividades: %d\n", len(activities))
-	fmt.Printf("Total de salas:       %d\n", len(rooms))
-	fmt.Printf("Total de profesores:  %d\n", len(teachers))
-	fmt.Printf("Cursos con restricción de sala: %d\n\n", len(roomConstraints))
-
-	// Contar por tipo de actividad
-	counts := map[domain.EventCategory]int{}
-	for _, a := range activities {
-		counts[a.Type]++
-	}
-	fmt.Println("Actividades por tipo:")
-	fmt.Printf("   CÁTEDRAS:     %d\n", counts[domain.CAT])
-	fmt.Printf("   AYUDANTÍAS:   %d\n", counts[domain.AY])
-	fmt.Printf("   LABORATORIOS: %d\n", counts[domain.LAB])
-
-	// Estadísticas del grafo
-	fmt.Println("\nGrafo de Conflictos:")
-	fmt.Printf("   Vértices (actividades): %d\n", conflictGraph.NumVertices())
-	fmt.Printf("   Aristas (conflictos):   %d\n", conflictGraph.NumEdges())
-
-	fmt.Println("\n═══════════════════════════════════════════════════════════")
-	fmt.Println("           EJECUTANDO SCHEDULER CON RESTRICCIONES")
-	fmt.Println("═══════════════════════════════════════════════════════════")
-
-	result := solver.IntegratedSchedulerWithConstraints(activities, conflictGraph, rooms, roomConstraints)
-
-	fmt.Printf("\nResultado del Scheduling:\n")
-	fmt.Printf("   Periodos utilizados:     %d\n", result.TotalPeriods)
-	fmt.Printf("   Bloques disponibles:     %d\n", domain.TotalBlocks)
-
-	// Contar actividades programadas
-	totalScheduled := 0
-	for _, p := range result.Periods {
-		for _, ra := range p.Assignments {
-			totalScheduled += len(ra.Activities)
-		}
-	}
+Sin subcomando se ejecuta solve. Use "timetabling <subcomando> -h" para ver los flags.
+`
 
-	fmt.Printf("   Actividades programadas: %d/%d\n", totalScheduled, len(activities))
-	fmt.Printf("   Sin programar (DUD):     %d\n", len(result.FinalDUD))
-
-	if len(result.FinalDUD) == 0 {
-		fmt.Println("   ÉXITO: Todas las actividades programadas")
-	} else if result.TotalPeriods > domain.TotalBlocks {
-		fmt.Println("   INFACTIBLE: Se excedieron los bloques disponibles")
-	} else {
-		fmt.Printf("   PARCIAL: %d actividades sin sala\n", len(result.FinalDUD))
-	}
-
-	// Mostrar distribución por periodo
-	fmt.Println("\nDistribución por periodo:")
-	fmt.Println("   Periodo | Bloque | Programadas | Salas Usadas")
-	fmt.Println("   --------|--------|-------------|-------------")
-
-	limit := 10
-	if len(result.Periods) < limit {
-		limit = len(result.Periods)
-	}
-	for i := 0; i < limit; i++ {
-		p := result.Periods[i]
-		count := 0
-		for _, ra := range p.Assignments {
-			count += len(ra.Activities)
-		}
-		fmt.Printf("   %7d | %6d | %11d | %d\n", p.Number, p.Block, count, len(p.Assignments))
-	}
-	if len(result.Periods) > limit {
-		fmt.Printf("   ... y %d periodos más\n", len(result.Periods)-limit)
-	}
-
-	// Estadísticas de uso de salas
-	roomUsage := make(map[string]int)
-	for _, p := range result.Periods {
-		for _, ra := range p.Assignments {
-			roomUsage[ra.RoomCode]++
-		}
-	}
-	fmt.Printf("\nSalas únicas utilizadas: %d de %d\n", len(roomUsage), len(rooms))
-
-	// Mostrar ejemplos de asignación del primer periodo
-	if len(result.Periods) > 0 {
-		p := result.Periods[0]
-		fmt.Println("\n   Ejemplo (Periodo 0):")
-		shown := 0
-		for _, ra := range p.Assignments {
-			if shown >= 5 {
-				break
-			}
-			for _, a := range ra.Activities {
-				fmt.Printf("   - %-25s → Sala: %-12s (%d est.)\n", a.Code, a.Room, a.Students)
-				shown++
-				if shown >= 5 {
-					break
-				}
-			}
-		}
-	}
-
-	// Mostrar todas las actividades sin sala
-	if len(result.FinalDUD) > 0 {
-		fmt.Printf("\n  TODAS las actividades sin sala (%d):\n", len(result.FinalDUD))
-		for _, a := range result.FinalDUD {
-			fmt.Printf("   - %-30s | %-10s | Curso: %-25s | %d est.\n", a.Code, a.Type, a.CourseName, a.Students)
-		}
-	}
-	if len(result.FinalDUD) == 0 {
-		fmt.Println("\n═══════════════════════════════════════════════════════════")
-		fmt.Println("           SIMULATED ANNEALING - OPTIMIZACIÓN")
-		fmt.Println("═══════════════════════════════════════════════════════════")
-
-		// Cargar prerrequisitos
-		prerequisites, err := loader.LoadPrerequisites("data/input/courses.json")
-		if err != nil {
-			log.Fatalf("Error cargando prerrequisitos: %v", err)
-		}
-
-		config := solver.DefaultSAConfig()
-		fmt.Printf("\n  Parámetros SA:\n")
-		fmt.Printf("   Temp. inicial:  %.0f\n", config.InitialTemp)
-		fmt.Printf("   Tasa enfriamiento: %.4f\n", config.CoolingRate)
-		fmt.Printf("   Iteraciones/T: %d\n", config.IterationsPerT)
-
-		fmt.Println("\n Ejecutando optimización (bloques + salas)...")
-		saResult := solver.SimulatedAnnealing(activities, rooms, config, prerequisites, planLocations, electives, roomConstraints)
-
-		fmt.Printf("\n Resultado SA:\n")
-		fmt.Printf("   Costo inicial:      %.0f\n", saResult.InitialCost)
-		fmt.Printf("   Costo final:        %.0f\n", saResult.FinalCost)
-		fmt.Printf("   Mejora:             %.1f%%\n", (1-saResult.FinalCost/saResult.InitialCost)*100)
-		fmt.Printf("   Iteraciones:        %d\n", saResult.Iterations)
-		fmt.Printf("   Mejoras aceptadas:  %d\n", saResult.Improvements)
-		fmt.Printf("\n Métricas de calidad:\n")
-		fmt.Printf("   Penalidad espejo:   %.0f\n", saResult.MirrorPenalty)
-		fmt.Printf("   AY en miércoles:    %.1f%%\n", saResult.WednesdayBonus)
-		fmt.Printf("   Prereq en mismo bloque: %.1f%%\n", saResult.PrereqBonus)
-		fmt.Printf("   Hermanos misma sala: %.1f%%\n", saResult.RoomConsistency)
-		fmt.Printf("   Sep. ideal (3 días): %.1f%%\n", saResult.DaySeparation)
-
-		// Exportar a JSON
-		outputFile := "data/output/schedule.json"
-		if err := exporter.ExportScheduleToJSON(activities, outputFile); err != nil {
-			fmt.Printf("\n Error exportando JSON: %v\n", err)
-		} else {
-			fmt.Printf("\n Horario exportado a: %s\n", outputFile)
-		}
+func main() {
+	args := os.Args[1:]
+
+	// Sin subcomando (o solo flags) se ejecuta solve, como antes
+	cmd := "solve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd = args[0]
+		args = args[1:]
+	}
+
+	switch cmd {
+	case "solve":
+		runSolve(args)
+	case "resolve":
+		runResolve(args)
+	case "validate":
+		runValidate(args)
+	case "validate-schedule":
+		runValidateSchedule(args)
+	case "exact":
+		runExact(args)
+	case "stats":
+		runStats(args)
+	case "diff":
+		runDiff(args)
+	case "export":
+		runExport(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "subcomando desconocido %q\n\n%s", cmd, usage)
+		os.Exit(2)
 	}
-
-	fmt.Println("\n═══════════════════════════════════════════════════════════")
 }
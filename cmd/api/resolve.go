@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"timetabling-UDP/internal/diff"
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+	"timetabling-UDP/internal/solver"
+)
+
+// runResolve implementa el subcomando resolve: re-optimiza un horario publicado con las entradas actuales
+// (sección nueva, profesor que dejó de estar disponible, sala cerrada) moviendo la menor cantidad de actividades.
+// Parte del horario anterior en vez del scheduler, y SA cobra cada actividad movida de su bloque o sala.
+func runResolve(args []string) {
+	defaults := solver.DefaultSAConfig()
+
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	from := fs.String("from", "data/output/schedule.json", "horario publicado (JSON)")
+	output := fs.String("out", "data/output/schedule_resolved.json", "archivo de salida del horario re-optimizado (JSON)")
+	initialTemp := fs.Float64("temp", 50, "temperatura inicial de SA (baja, para no desordenar el horario publicado)")
+	coolingRate := fs.Float64("cooling", 0.995, "tasa de enfriamiento de SA")
+	minTemp := fs.Float64("min-temp", defaults.MinTemp, "temperatura mínima de SA")
+	iterations := fs.Int("iterations", 2000, "iteraciones por nivel de temperatura")
+	weightsPath := fs.String("weights", "data/input/objective_weights.json", "pesos de la función objetivo (JSON, vacío = por defecto)")
+	movedBlock := fs.Float64("moved-block", defaults.Weights.MovedBlock, "costo por actividad fuera de su bloque publicado (reemplaza el de -weights)")
+	movedRoom := fs.Float64("moved-room", defaults.Weights.MovedRoom, "costo por actividad fuera de su sala publicada (reemplaza el de -weights)")
+	seed := fs.Int64("seed", 0, "semilla aleatoria para reproducir una ejecución (0 = elegir una)")
+	moves := fs.String("moves", "", "probabilidad de cada movimiento de SA (vacío = por defecto, ver solve -h)")
+	verbose := fs.Bool("v", false, "detalla los cambios por profesor y por sala, y las violaciones")
+	allowPartial := fs.Bool("allow-partial", false, "exporta el horario aunque el validador encuentre violaciones duras")
+	fs.Parse(args)
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// -moved-block y -moved-room solo reemplazan los pesos si se indicaron explícitamente
+	weights, err := loadWeights(*weightsPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "moved-block":
+			weights.MovedBlock = *movedBlock
+		case "moved-room":
+			weights.MovedRoom = *movedRoom
+		}
+	})
+
+	previous, err := loader.ImportScheduleFromJSON(*from)
+	if err != nil {
+		log.Fatalf("Error leyendo horario %s: %v", *from, err)
+	}
+
+	// Estado inicial: el horario publicado aplicado a las actividades de la oferta actual
+	activities := pr.activities
+	match := loader.ApplySchedule(activities, previous)
+	anchors := solver.AnchorsFrom(activities)
+	released := solver.ReleaseInvalidPlacements(activities, pr.rooms, pr.roomConstraints, pr.teachers, pr.planLocations, pr.electives)
+
+	fmt.Printf("Horario publicado: %s (%d actividades)\n", *from, len(previous))
+	fmt.Printf("\n Cambios en las entradas:\n")
+	fmt.Printf("   Sin cambios:          %d\n", match.Matched-len(match.Changed))
+	fmt.Printf("   Datos modificados:    %d\n", len(match.Changed))
+	fmt.Printf("   Nuevas:               %d\n", len(match.New))
+	fmt.Printf("   Ya no están:          %d\n", len(match.Removed))
+	if len(match.Changed) > 0 {
+		fmt.Printf("   Modificadas: %s\n", joinLimited(match.Changed, 8))
+	}
+	if len(match.New) > 0 {
+		fmt.Printf("   Nuevas: %s\n", joinLimited(match.New, 8))
+	}
+	if len(match.Removed) > 0 {
+		fmt.Printf("   Ya no están: %s\n", joinLimited(match.Removed, 8))
+	}
+	if len(released) > 0 {
+		fmt.Printf("\n Ubicaciones publicadas que ya no son válidas (%d):\n", len(released))
+		for _, r := range released {
+			what := "sala " + r.Room
+			if r.Freed {
+				what = domain.BlockLabel(r.Block)
+			}
+			fmt.Printf("   - %-30s | %-22s | %-22s | %s\n", r.Code, what, r.Reason, r.Other)
+		}
+	}
+
+	moveProbs := solver.DefaultMoveProbabilities()
+	if *moves != "" {
+		moveProbs, err = solver.ParseMoveProbabilities(*moves)
+		if err != nil {
+			log.Fatalf("Error en -moves: %v", err)
+		}
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	config := defaults
+	config.InitialTemp = *initialTemp
+	config.CoolingRate = *coolingRate
+	config.MinTemp = *minTemp
+	config.IterationsPerT = *iterations
+	config.Seed = *seed
+	config.Weights = weights
+	config.Moves = moveProbs
+	config.Anchors = anchors
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("           RE-OPTIMIZACIÓN CON PERTURBACIÓN MÍNIMA")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Printf("\nSemilla: %d\n", *seed)
+	fmt.Printf("   Costo por actividad movida: bloque %.0f, sala %.0f\n", weights.MovedBlock, weights.MovedRoom)
+
+	saResult := solver.SimulatedAnnealing(activities, pr.rooms, config, pr.prerequisites, pr.planLocations, pr.electives, pr.roomConstraints, pr.teachers)
+
+	fmt.Printf("\n Resultado SA:\n")
+	fmt.Printf("   Costo inicial:      %.0f\n", saResult.InitialCost)
+	fmt.Printf("   Costo final:        %.0f\n", saResult.FinalCost)
+	fmt.Printf("   Iteraciones:        %d\n", saResult.Iterations)
+	if saResult.Unscheduled > 0 {
+		fmt.Printf("   Insertadas:         %d (%d sin violaciones)\n", saResult.Unscheduled, saResult.Repaired)
+	}
+	fmt.Printf("   Movidas de bloque:  %d de %d publicadas\n", saResult.MovedBlocks, len(anchors))
+	fmt.Printf("   Movidas solo de sala: %d\n", saResult.MovedRooms)
+	if len(saResult.HardViolations) > 0 {
+		fmt.Printf("\n HORARIO PARCIAL: %d violaciones duras pendientes\n", len(saResult.HardViolations))
+		for _, v := range saResult.HardViolations {
+			fmt.Printf("   - %-30s | %-22s | %-22s | %s\n", v.ActivityCode, v.Kind, domain.BlockLabel(v.Block), v.Other)
+		}
+		fmt.Printf("   Subir -temp (o bajar -moved-block) permite mover más actividades para resolverlas\n")
+	}
+
+	// Lo que cambió respecto del horario publicado, para avisar a profesores y salas
+	printDiff(diff.Compare(previous, activities), *verbose)
+
+	violations := validateSchedule(pr, activities, *verbose)
+	exportIfValid(activities, saResult.TeacherSatisfaction, violations, *output, *allowPartial)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/graph"
+	"timetabling-UDP/internal/loader"
+	"timetabling-UDP/internal/solver"
+)
+
+// runSolve implementa el subcomando solve: scheduler con restricciones + SA o búsqueda tabú + exportación
+func runSolve(args []string) {
+	defaults := solver.DefaultSAConfig()
+	tabuDefaults := solver.DefaultTabuConfig()
+
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	algo := fs.String("algo", "sa", "metaheurística de optimización: sa (simulated annealing) o tabu (búsqueda tabú)")
+	coloring := fs.String("coloring", solver.ColoringDuttonBrigham, "estrategia de coloreo del scheduler: dutton-brigham, dsatur, rlf o welsh-powell")
+	output := fs.String("out", "data/output/schedule.json", "archivo de salida del horario (JSON)")
+	initialTemp := fs.Float64("temp", defaults.InitialTemp, "temperatura inicial de SA")
+	coolingRate := fs.Float64("cooling", defaults.CoolingRate, "tasa de enfriamiento de SA")
+	minTemp := fs.Float64("min-temp", defaults.MinTemp, "temperatura mínima de SA")
+	iterations := fs.Int("iterations", defaults.IterationsPerT, "iteraciones por nivel de temperatura")
+	tabuIterations := fs.Int("tabu-iterations", tabuDefaults.Iterations, "iteraciones de la búsqueda tabú")
+	tabuNeighbors := fs.Int("tabu-neighbors", tabuDefaults.Neighbors, "movimientos candidatos evaluados por iteración de la búsqueda tabú")
+	tabuTenure := fs.Int("tabu-tenure", tabuDefaults.Tenure, "iteraciones que un movimiento inverso queda prohibido")
+	tabuStall := fs.Int("tabu-stall", tabuDefaults.MaxNoImprove, "detener la búsqueda tabú tras N iteraciones sin mejorar (0 = no detener)")
+	aspiration := fs.Bool("aspiration", tabuDefaults.Aspiration, "aceptar movimientos tabú que mejoran el mejor costo encontrado")
+	weightsPath := fs.String("weights", "data/input/objective_weights.json", "pesos de la función objetivo (JSON, vacío = por defecto)")
+	prefWeight := fs.Float64("pref-weight", defaults.Weights.TeacherPreference, "costo por nivel de preferencia de profesor (reemplaza el de -weights)")
+	seed := fs.Int64("seed", 0, "semilla aleatoria para reproducir una ejecución (0 = elegir una)")
+	checkEvery := fs.Int("check-cost", 0, "depuración: cada N iteraciones verifica que el costo incremental coincida con el recálculo (0 = nunca)")
+	moves := fs.String("moves", "", "probabilidad de cada movimiento de SA y tabú, p. ej. \"block=0.35,room=0.35,swap=0.15,sibling=0.1,kempe=0.05\" (vacío = por defecto)")
+	verbose := fs.Bool("v", false, "muestra el detalle por periodo y por profesor")
+	allowPartial := fs.Bool("allow-partial", false, "exporta el horario aunque el validador encuentre violaciones duras")
+	fs.Parse(args)
+	if *algo != "sa" && *algo != "tabu" {
+		log.Fatalf("Error: -algo %q desconocido (usar sa o tabu)", *algo)
+	}
+	strategy, err := solver.ColoringStrategyByName(*coloring)
+	if err != nil {
+		log.Fatalf("Error en -coloring: %v", err)
+	}
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// Pesos de la función objetivo, -pref-weight solo los reemplaza si se indicó explícitamente
+	weights, err := loadWeights(*weightsPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "pref-weight" {
+			weights.TeacherPreference = *prefWeight
+		}
+	})
+	activities := pr.activities
+
+	// Los problemas de las entradas no detienen la ejecución, pero se informan para revisarlos con validate
+	if issues := validateInputs(paths); len(issues) > 0 {
+		errors := 0
+		for _, issue := range issues {
+			if issue.Severity == loader.SeverityError {
+				errors++
+			}
+		}
+		fmt.Printf("Entradas con %d errores y %d advertencias (ver \"validate\" para el detalle)\n", errors, len(issues)-errors)
+	}
+
+	// Construir grafo de conflictos con cliques de semestre (sin electivos)
+	conflictGraph := graph.BuildFromActivitiesWithCliques(activities, pr.planLocations, pr.electives)
+	printProblemStats(pr, conflictGraph)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("           EJECUTANDO SCHEDULER CON RESTRICCIONES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+
+	// Sin semilla se elige una basada en la hora y se informa, para poder reproducir cualquier ejecución
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Printf("\nSemilla: %d\n", *seed)
+	fmt.Printf("Coloreo: %s\n", strategy.Name())
+
+	// Los empates del coloreo se deciden al azar de forma reproducible
+	greedyRNG := rand.New(rand.NewSource(*seed))
+
+	result := solver.IntegratedSchedulerWithConstraints(activities, conflictGraph, pr.rooms, pr.roomConstraints, pr.teachers, pr.planLocations, strategy, greedyRNG)
+
+	fmt.Printf("\nResultado del Scheduling:\n")
+	fmt.Printf("   Periodos utilizados:     %d\n", result.TotalPeriods)
+	fmt.Printf("   Bloques disponibles:     %d\n", domain.TotalBlocks)
+
+	// Contar actividades programadas
+	totalScheduled := 0
+	for _, p := range result.Periods {
+		for _, ra := range p.Assignments {
+			totalScheduled += len(ra.Activities)
+		}
+	}
+
+	fmt.Printf("   Actividades programadas: %d/%d\n", totalScheduled, len(activities))
+	fmt.Printf("   Sin programar (DUD):     %d\n", len(result.FinalDUD))
+
+	if len(result.FinalDUD) == 0 {
+		fmt.Println("   ÉXITO: Todas las actividades programadas")
+	} else if result.TotalPeriods > domain.TotalBlocks {
+		fmt.Println("   INFACTIBLE: Se excedieron los bloques disponibles")
+	} else {
+		fmt.Printf("   PARCIAL: %d actividades sin sala\n", len(result.FinalDUD))
+	}
+
+	if *verbose {
+		printPeriodDetails(result, len(pr.rooms))
+	}
+
+	// Explicar cada actividad sin programar contra el grafo completo (el scheduler vació el suyo)
+	if len(result.FinalDUD) > 0 {
+		fullGraph := graph.BuildFromActivitiesWithCliques(activities, pr.planLocations, pr.electives)
+		reports := solver.ExplainUnscheduled(result.FinalDUD, activities, fullGraph, pr.rooms, pr.roomConstraints, pr.teachers, pr.planLocations)
+		printDUDReports(reports)
+	}
+	moveProbs := solver.DefaultMoveProbabilities()
+	if *moves != "" {
+		moveProbs, err = solver.ParseMoveProbabilities(*moves)
+		if err != nil {
+			log.Fatalf("Error en -moves: %v", err)
+		}
+	}
+
+	var saResult solver.SAResult
+	var algoName string
+	switch *algo {
+	case "sa":
+		algoName = "SA"
+		fmt.Println("\n═══════════════════════════════════════════════════════════")
+		fmt.Println("           SIMULATED ANNEALING - OPTIMIZACIÓN")
+		fmt.Println("═══════════════════════════════════════════════════════════")
+
+		config := defaults
+		config.InitialTemp = *initialTemp
+		config.CoolingRate = *coolingRate
+		config.MinTemp = *minTemp
+		config.IterationsPerT = *iterations
+		config.Seed = *seed
+		config.Weights = weights
+		config.CheckEvery = *checkEvery
+		config.Moves = moveProbs
+
+		fmt.Printf("\n  Parámetros SA:\n")
+		fmt.Printf("   Temp. inicial:  %.0f\n", config.InitialTemp)
+		fmt.Printf("   Tasa enfriamiento: %.4f\n", config.CoolingRate)
+		fmt.Printf("   Iteraciones/T: %d\n", config.IterationsPerT)
+
+		fmt.Println("\n Ejecutando optimización (bloques + salas)...")
+		saResult = solver.SimulatedAnnealing(activities, pr.rooms, config, pr.prerequisites, pr.planLocations, pr.electives, pr.roomConstraints, pr.teachers)
+	case "tabu":
+		algoName = "tabú"
+		fmt.Println("\n═══════════════════════════════════════════════════════════")
+		fmt.Println("           BÚSQUEDA TABÚ - OPTIMIZACIÓN")
+		fmt.Println("═══════════════════════════════════════════════════════════")
+
+		config := tabuDefaults
+		config.Iterations = *tabuIterations
+		config.Neighbors = *tabuNeighbors
+		config.Tenure = *tabuTenure
+		config.MaxNoImprove = *tabuStall
+		config.Aspiration = *aspiration
+		config.Seed = *seed
+		config.Weights = weights
+		config.CheckEvery = *checkEvery
+		config.Moves = moveProbs
+
+		fmt.Printf("\n  Parámetros tabú:\n")
+		fmt.Printf("   Iteraciones:    %d (máx. %d sin mejorar)\n", config.Iterations, config.MaxNoImprove)
+		fmt.Printf("   Vecinos/iter.:  %d\n", config.Neighbors)
+		fmt.Printf("   Tenure:         %d\n", config.Tenure)
+		fmt.Printf("   Aspiración:     %v\n", config.Aspiration)
+
+		fmt.Println("\n Ejecutando optimización (bloques + salas)...")
+		saResult = solver.TabuSearch(activities, pr.rooms, config, pr.prerequisites, pr.planLocations, pr.electives, pr.roomConstraints, pr.teachers)
+	}
+
+	fmt.Printf("\n Resultado %s:\n", algoName)
+	fmt.Printf("   Costo inicial:      %.0f\n", saResult.InitialCost)
+	fmt.Printf("   Costo final:        %.0f\n", saResult.FinalCost)
+	fmt.Printf("   Mejora:             %.1f%%\n", (1-saResult.FinalCost/saResult.InitialCost)*100)
+	fmt.Printf("   Iteraciones:        %d\n", saResult.Iterations)
+	fmt.Printf("   Mejoras aceptadas:  %d\n", saResult.Improvements)
+	if *verbose {
+		names := make([]string, 0, len(saResult.CostTerms))
+		for name := range saResult.CostTerms {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("   Costo por término:\n")
+		for _, name := range names {
+			fmt.Printf("      %-20s %.0f\n", name, saResult.CostTerms[name])
+		}
+		fmt.Printf("   Movimientos:          intentos  factibles  aceptados  mejoras\n")
+		for _, name := range solver.MoveNames {
+			ms := saResult.MoveStats[name]
+			fmt.Printf("      %-16s %10d %10d %10d %8d\n", name, ms.Attempted, ms.Feasible, ms.Accepted, ms.Improving)
+		}
+	}
+	if saResult.Unscheduled > 0 {
+		fmt.Printf("\n Reparación de actividades sin programar:\n")
+		fmt.Printf("   Insertadas:           %d (%d sin violaciones)\n", saResult.Unscheduled, saResult.Repaired)
+		fmt.Printf("   Violaciones iniciales: %d\n", saResult.InitialHard)
+	}
+	if len(saResult.HardViolations) > 0 {
+		fmt.Printf("\n HORARIO PARCIAL: %d violaciones duras pendientes\n", len(saResult.HardViolations))
+		for _, v := range saResult.HardViolations {
+			fmt.Printf("   - %-30s | %-22s | %-22s | %s\n", v.ActivityCode, v.Kind, domain.BlockLabel(v.Block), v.Other)
+		}
+	}
+	fmt.Printf("\n Métricas de calidad:\n")
+	fmt.Printf("   Penalidad espejo:   %.0f\n", saResult.MirrorPenalty)
+	fmt.Printf("   AY en miércoles:    %.1f%%\n", saResult.WednesdayBonus)
+	fmt.Printf("   Prereq en mismo bloque: %.1f%%\n", saResult.PrereqBonus)
+	fmt.Printf("   Hermanos misma sala: %.1f%%\n", saResult.RoomConsistency)
+	fmt.Printf("   Sep. ideal (3 días): %.1f%%\n", saResult.DaySeparation)
+	fmt.Printf("   Satisfacción profesores: %.1f%% (%d con preferencias)\n", saResult.AvgTeacherSatisfaction, len(saResult.TeacherSatisfaction))
+	if *verbose {
+		for _, ts := range saResult.TeacherSatisfaction {
+			if ts.UndesiredBlocks > 0 {
+				fmt.Printf("   - %-30s | %5.1f%% | %d bloque(s) no deseados\n", ts.Teacher, ts.Satisfaction, ts.UndesiredBlocks)
+			}
+		}
+	}
+
+	// Validar el horario final con el validador independiente antes de exportarlo
+	violations := validateSchedule(pr, activities, *verbose)
+	exportIfValid(activities, saResult.TeacherSatisfaction, violations, *output, *allowPartial)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+}
+
+// printPeriodDetails muestra la distribución por periodo, el uso de salas y un ejemplo de asignación
+func printPeriodDetails(result solver.TimetableResult, totalRooms int) {
+	fmt.Println("\nDistribución por periodo:")
+	fmt.Println("   Periodo | Bloque | Programadas | Salas Usadas")
+	fmt.Println("   --------|--------|-------------|-------------")
+
+	limit := 10
+	if len(result.Periods) < limit {
+		limit = len(result.Periods)
+	}
+	for i := 0; i < limit; i++ {
+		p := result.Periods[i]
+		count := 0
+		for _, ra := range p.Assignments {
+			count += len(ra.Activities)
+		}
+		fmt.Printf("   %7d | %6d | %11d | %d\n", p.Number, p.Block, count, len(p.Assignments))
+	}
+	if len(result.Periods) > limit {
+		fmt.Printf("   ... y %d periodos más\n", len(result.Periods)-limit)
+	}
+
+	// Estadísticas de uso de salas
+	roomUsage := make(map[string]int)
+	for _, p := range result.Periods {
+		for _, ra := range p.Assignments {
+			roomUsage[ra.RoomCode]++
+		}
+	}
+	fmt.Printf("\nSalas únicas utilizadas: %d de %d\n", len(roomUsage), totalRooms)
+
+	// Mostrar ejemplos de asignación del primer periodo
+	if len(result.Periods) > 0 {
+		p := result.Periods[0]
+		fmt.Println("\n   Ejemplo (Periodo 0):")
+		shown := 0
+		for _, ra := range p.Assignments {
+			if shown >= 5 {
+				break
+			}
+			for _, a := range ra.Activities {
+				fmt.Printf("   - %-25s → Sala: %-12s (%d est.)\n", a.Code, a.Room, a.Students)
+				shown++
+				if shown >= 5 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// printDUDReports muestra el diagnóstico de las actividades sin programar
+func printDUDReports(reports []solver.DUDReport) {
+	fmt.Printf("\n  Actividades sin programar (%d):\n", len(reports))
+	for _, r := range reports {
+		a := r.Activity
+		fmt.Printf("\n   - %s | %s | Curso: %s | %d est.\n", a.Code, a.Type, a.CourseName, a.Students)
+
+		reasons := make([]string, len(r.Reasons))
+		for i, reason := range r.Reasons {
+			reasons[i] = string(reason)
+		}
+		fmt.Printf("     Causas:  %s\n", strings.Join(reasons, ", "))
+		fmt.Printf("     Salas:   %d permitidas, %d con capacidad (máx. %d)\n", r.AllowedRooms, r.FittingRooms, r.MaxCapacity)
+		if r.CandidateBlocks > 0 {
+			fmt.Printf("     Bloques: %d candidatos, %d libres, grado %d\n", r.CandidateBlocks, r.FreeBlocks, r.Degree)
+			for _, reason := range r.Reasons {
+				if n := r.BlockCauses[reason]; n > 0 {
+					fmt.Printf("        %-24s %d bloques\n", reason, n)
+				}
+			}
+		}
+		if len(r.UnavailableTeachers) > 0 {
+			fmt.Printf("     Profesores no disponibles: %s\n", strings.Join(r.UnavailableTeachers, ", "))
+		}
+		if len(r.Conflicts) > 0 {
+			fmt.Printf("     En conflicto con: %s\n", joinLimited(r.Conflicts, 8))
+		}
+		if r.Suggestion != "" {
+			fmt.Printf("     Sugerencia: %s\n", r.Suggestion)
+		}
+	}
+}
+
+// joinLimited une los primeros n elementos e indica cuántos se omiten
+func joinLimited(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s y %d más", strings.Join(items[:n], ", "), len(items)-n)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/graph"
+	"timetabling-UDP/internal/solver"
+)
+
+// runStats implementa el subcomando stats
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	verbose := fs.Bool("v", false, "muestra las actividades con más conflictos")
+	fs.Parse(args)
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	conflictGraph := graph.BuildFromActivitiesWithCliques(pr.activities, pr.planLocations, pr.electives)
+	printProblemStats(pr, conflictGraph)
+
+	// Grado de los vértices
+	maxDegree := 0
+	totalDegree := 0
+	for id := range conflictGraph.Vertices {
+		d := conflictGraph.Degree(id)
+		totalDegree += d
+		if d > maxDegree {
+			maxDegree = d
+		}
+	}
+	avgDegree := 0.0
+	if conflictGraph.NumVertices() > 0 {
+		avgDegree = float64(totalDegree) / float64(conflictGraph.NumVertices())
+	}
+	fmt.Printf("   Grado máximo:           %d\n", maxDegree)
+	fmt.Printf("   Grado promedio:         %.1f\n", avgDegree)
+
+	printColoringStats(pr, conflictGraph)
+
+	if *verbose {
+		ids := make([]int, 0, conflictGraph.NumVertices())
+		for id := range conflictGraph.Vertices {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			di, dj := conflictGraph.Degree(ids[i]), conflictGraph.Degree(ids[j])
+			if di != dj {
+				return di > dj
+			}
+			return ids[i] < ids[j]
+		})
+		limit := 10
+		if len(ids) < limit {
+			limit = len(ids)
+		}
+		fmt.Println("\nActividades con más conflictos:")
+		for _, id := range ids[:limit] {
+			a := conflictGraph.Vertices[id]
+			fmt.Printf("   - %-30s | grado %d\n", a.Code, conflictGraph.Degree(id))
+		}
+	}
+}
+
+// printColoringStats compara las estrategias de coloreo: colores del grafo completo y resultado del scheduler
+// integrado con cada una (sobre una copia de las actividades)
+func printColoringStats(pr *problem, conflictGraph *graph.ConflictGraph) {
+	fmt.Println("\nColoreo por estrategia:")
+	fmt.Println("   Estrategia       | Colores | Periodos | Sin programar (DUD)")
+	fmt.Println("   -----------------|---------|----------|--------------------")
+	for _, strategy := range solver.ColoringStrategies() {
+		colors := len(solver.GreedyColoring(conflictGraph, strategy))
+
+		activities := make([]domain.Activity, len(pr.activities))
+		copy(activities, pr.activities)
+		G := graph.BuildFromActivitiesWithCliques(activities, pr.planLocations, pr.electives)
+		result := solver.IntegratedSchedulerWithConstraints(activities, G, pr.rooms, pr.roomConstraints, pr.teachers, pr.planLocations, strategy, nil)
+
+		fmt.Printf("   %-16s | %7d | %8d | %d\n", strategy.Name(), colors, result.TotalPeriods, len(result.FinalDUD))
+	}
+}
+
+// printProblemStats imprime las estadísticas generales de las entradas y del grafo
+func printProblemStats(pr *problem, conflictGraph *graph.ConflictGraph) {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("           UDP TIMETABLING")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Printf("Total de actividades: %d\n", len(pr.activities))
+	fmt.Printf("Grilla semanal:       %d días x %d bloques\n", domain.DaysPerWeek, domain.BlocksPerDay)
+	fmt.Printf("Total de salas:       %d\n", len(pr.rooms))
+	fmt.Printf("Total de profesores:  %d\n", len(pr.teachers))
+	fmt.Printf("Cursos con restricción de sala: %d\n\n", len(pr.roomConstraints))
+
+	// Contar por tipo de actividad
+	counts := map[domain.EventCategory]int{}
+	for _, a := range pr.activities {
+		counts[a.Type]++
+	}
+	fmt.Println("Actividades por tipo:")
+	fmt.Printf("   CÁTEDRAS:     %d\n", counts[domain.CAT])
+	fmt.Printf("   AYUDANTÍAS:   %d\n", counts[domain.AY])
+	fmt.Printf("   LABORATORIOS: %d\n", counts[domain.LAB])
+
+	// Estadísticas del grafo
+	fmt.Println("\nGrafo de Conflictos:")
+	fmt.Printf("   Vértices (actividades): %d\n", conflictGraph.NumVertices())
+	fmt.Printf("   Aristas (conflictos):   %d\n", conflictGraph.NumEdges())
+}
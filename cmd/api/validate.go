@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/exporter"
+	"timetabling-UDP/internal/loader"
+	"timetabling-UDP/internal/validator"
+)
+
+// runValidate implementa el subcomando validate: reporta los problemas de las entradas con archivo y registro
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	quiet := fs.Bool("q", false, "muestra solo los errores, no las advertencias")
+	fs.Parse(args)
+
+	// La grilla se necesita para validar disponibilidades y duraciones
+	grid, err := loader.LoadTimeGrid(paths.grid)
+	if err != nil {
+		log.Fatalf("Error: %s: %v", paths.grid, err)
+	}
+	domain.SetTimeGrid(grid)
+
+	issues := validateInputs(paths)
+
+	errors, warnings := 0, 0
+	for _, issue := range issues {
+		if issue.Severity == loader.SeverityError {
+			errors++
+		} else {
+			warnings++
+			if *quiet {
+				continue
+			}
+		}
+		fmt.Println(issue)
+	}
+
+	if errors > 0 {
+		fmt.Printf("\n%d errores, %d advertencias\n", errors, warnings)
+		os.Exit(1)
+	}
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if len(issues) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("Entradas válidas (%d advertencias):\n", warnings)
+	fmt.Printf("   Actividades: %d\n", len(pr.activities))
+	fmt.Printf("   Salas:       %d\n", len(pr.rooms))
+	fmt.Printf("   Profesores:  %d\n", len(pr.teachers))
+}
+
+// runValidateSchedule implementa el subcomando validate-schedule: revisa las reglas duras de un schedule.json
+// existente contra las entradas
+func runValidateSchedule(args []string) {
+	fs := flag.NewFlagSet("validate-schedule", flag.ExitOnError)
+	paths := addInputFlags(fs)
+	input := fs.String("in", "data/output/schedule.json", "horario a validar (JSON)")
+	verbose := fs.Bool("v", false, "lista todas las violaciones, no solo el conteo por regla")
+	fs.Parse(args)
+
+	pr, err := loadProblem(paths)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	activities, err := loader.ImportScheduleFromJSON(*input)
+	if err != nil {
+		log.Fatalf("Error leyendo horario %s: %v", *input, err)
+	}
+
+	fmt.Printf("Horario: %s (%d actividades)\n", *input, len(activities))
+
+	// Cobertura respecto de la oferta actual, sobre una copia para no tocar las actividades cargadas
+	current := make([]domain.Activity, len(pr.activities))
+	copy(current, pr.activities)
+	match := loader.ApplySchedule(current, activities)
+	if len(match.New) > 0 {
+		fmt.Printf("   Actividades de la oferta que no están en el horario: %s\n", joinLimited(match.New, 8))
+	}
+	if len(match.Removed) > 0 {
+		fmt.Printf("   Actividades del horario que ya no están en la oferta: %s\n", joinLimited(match.Removed, 8))
+	}
+	violations := validateSchedule(pr, activities, *verbose)
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+}
+
+// validateSchedule revisa el horario con el validador independiente e imprime el resultado
+func validateSchedule(pr *problem, activities []domain.Activity, verbose bool) []validator.Violation {
+	violations := validator.Validate(activities, validator.Rules{
+		Rooms:         pr.rooms,
+		Teachers:      pr.teachers,
+		Constraints:   pr.roomConstraints,
+		PlanLocations: pr.planLocations,
+		Electives:     pr.electives,
+	})
+
+	fmt.Printf("\n Validación del horario:\n")
+	if len(violations) == 0 {
+		fmt.Println("   Cumple todas las reglas duras")
+		return nil
+	}
+	fmt.Printf("   VIOLACIONES: %d\n", len(violations))
+	counts := validator.CountByKind(violations)
+	for _, kind := range validator.Kinds {
+		if counts[kind] > 0 {
+			fmt.Printf("      %-24s %d\n", kind, counts[kind])
+		}
+	}
+	if verbose {
+		for _, v := range violations {
+			fmt.Printf("   - %s\n", v)
+		}
+	}
+	return violations
+}
+
+// exportIfValid exporta el horario a JSON solo si el validador no encontró violaciones o si se pidió
+// -allow-partial; si no, termina con código 1 sin escribir el archivo (como validate-schedule)
+func exportIfValid(activities []domain.Activity, satisfaction []domain.TeacherSatisfaction, violations []validator.Violation, output string, allowPartial bool) {
+	if len(violations) > 0 && !allowPartial {
+		fmt.Printf("\n Horario con %d violaciones duras: no se exporta a %s (usar -allow-partial para exportarlo igual)\n", len(violations), output)
+		os.Exit(1)
+	}
+	if err := exporter.ExportScheduleToJSON(activities, satisfaction, output); err != nil {
+		log.Fatalf("Error exportando JSON: %v", err)
+	}
+	if len(violations) > 0 {
+		fmt.Printf("\n Horario PARCIAL (%d violaciones duras) exportado a: %s\n", len(violations), output)
+		return
+	}
+	fmt.Printf("\n Horario exportado a: %s\n", output)
+}
@@ -0,0 +1,289 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// Change describe cómo cambió una actividad entre dos horarios, emparejadas por código. Una actividad nueva solo
+// tiene el estado After y una eliminada solo el Before; los bloques sin asignar son -1.
+type Change struct {
+	Code       string
+	CourseCode string
+	CourseName string
+	Type       domain.EventCategory
+	Added      bool // solo está en el horario nuevo
+	Removed    bool // solo está en el horario anterior
+
+	BeforeBlock    int
+	AfterBlock     int
+	BeforeDuration int
+	AfterDuration  int
+	BeforeRoom     string
+	AfterRoom      string
+	BeforeTeachers []string
+	AfterTeachers  []string
+}
+
+// BlockChanged indica si cambió el horario (bloque o duración) de una actividad presente en ambos horarios
+func (c *Change) BlockChanged() bool {
+	return !c.Added && !c.Removed && (c.BeforeBlock != c.AfterBlock || c.BeforeDuration != c.AfterDuration)
+}
+
+// RoomChanged indica si cambió la sala de una actividad presente en ambos horarios
+func (c *Change) RoomChanged() bool {
+	return !c.Added && !c.Removed && c.BeforeRoom != c.AfterRoom
+}
+
+// TeachersChanged indica si cambiaron los profesores de una actividad presente en ambos horarios
+func (c *Change) TeachersChanged() bool {
+	return !c.Added && !c.Removed && !sameNames(c.BeforeTeachers, c.AfterTeachers)
+}
+
+// Teachers retorna los profesores afectados por el cambio: los de antes y los de después
+func (c *Change) Teachers() []string {
+	return union(c.BeforeTeachers, c.AfterTeachers)
+}
+
+// Rooms retorna las salas afectadas: la anterior y la nueva, si cambió el horario o la sala (un cambio solo de
+// profesor no afecta a la sala)
+func (c *Change) Rooms() []string {
+	if !c.Added && !c.Removed && !c.BlockChanged() && !c.RoomChanged() {
+		return nil
+	}
+	var rooms []string
+	if c.BeforeRoom != "" {
+		rooms = append(rooms, c.BeforeRoom)
+	}
+	if c.AfterRoom != "" && c.AfterRoom != c.BeforeRoom {
+		rooms = append(rooms, c.AfterRoom)
+	}
+	return rooms
+}
+
+func (c *Change) String() string {
+	switch {
+	case c.Added:
+		return fmt.Sprintf("%s: nueva, %s", c.Code, placement(c.AfterBlock, c.AfterDuration, c.AfterRoom))
+	case c.Removed:
+		return fmt.Sprintf("%s: eliminada, estaba en %s", c.Code, placement(c.BeforeBlock, c.BeforeDuration, c.BeforeRoom))
+	}
+	var parts []string
+	if c.BlockChanged() {
+		parts = append(parts, fmt.Sprintf("horario %s -> %s", timeLabel(c.BeforeBlock, c.BeforeDuration), timeLabel(c.AfterBlock, c.AfterDuration)))
+	}
+	if c.RoomChanged() {
+		parts = append(parts, fmt.Sprintf("sala %s -> %s", roomLabel(c.BeforeRoom), roomLabel(c.AfterRoom)))
+	}
+	if c.TeachersChanged() {
+		parts = append(parts, fmt.Sprintf("profesores %s -> %s", teachersLabel(c.BeforeTeachers), teachersLabel(c.AfterTeachers)))
+	}
+	return c.Code + ": " + strings.Join(parts, ", ")
+}
+
+// Report es el resultado de comparar dos horarios
+type Report struct {
+	Changes   []Change // ordenados por curso y código
+	Unchanged int      // actividades presentes en ambos horarios sin cambios
+}
+
+// Counts resume la cantidad de cambios por tipo; una actividad puede contar en varios
+type Counts struct {
+	Changed  int // actividades presentes en ambos horarios con algún cambio
+	Block    int
+	Room     int
+	Teachers int
+	Added    int
+	Removed  int
+}
+
+// Group son los cambios que afectan a un curso, profesor o sala
+type Group struct {
+	Name    string
+	Changes []*Change
+}
+
+// Compare compara dos horarios emparejando las actividades por código y retorna las que cambiaron de bloque,
+// duración, sala o profesores, y las que solo están en uno de ellos
+func Compare(before, after []domain.Activity) Report {
+	byCode := make(map[string]*domain.Activity, len(before))
+	for i := range before {
+		byCode[before[i].Code] = &before[i]
+	}
+
+	var report Report
+	seen := make(map[string]bool, len(after))
+	for i := range after {
+		a := &after[i]
+		seen[a.Code] = true
+		b, ok := byCode[a.Code]
+		if !ok {
+			c := newChange(a)
+			c.Added = true
+			c.BeforeBlock = -1
+			c.AfterBlock, c.AfterDuration, c.AfterRoom, c.AfterTeachers = a.Block, a.Span(), a.Room, a.TeacherNames
+			report.Changes = append(report.Changes, c)
+			continue
+		}
+
+		c := newChange(a)
+		c.BeforeBlock, c.BeforeDuration, c.BeforeRoom, c.BeforeTeachers = b.Block, b.Span(), b.Room, b.TeacherNames
+		c.AfterBlock, c.AfterDuration, c.AfterRoom, c.AfterTeachers = a.Block, a.Span(), a.Room, a.TeacherNames
+		if c.BlockChanged() || c.RoomChanged() || c.TeachersChanged() {
+			report.Changes = append(report.Changes, c)
+		} else {
+			report.Unchanged++
+		}
+	}
+	for i := range before {
+		b := &before[i]
+		if seen[b.Code] {
+			continue
+		}
+		c := newChange(b)
+		c.Removed = true
+		c.AfterBlock = -1
+		c.BeforeBlock, c.BeforeDuration, c.BeforeRoom, c.BeforeTeachers = b.Block, b.Span(), b.Room, b.TeacherNames
+		report.Changes = append(report.Changes, c)
+	}
+
+	sort.Slice(report.Changes, func(i, j int) bool {
+		if report.Changes[i].CourseCode != report.Changes[j].CourseCode {
+			return report.Changes[i].CourseCode < report.Changes[j].CourseCode
+		}
+		return report.Changes[i].Code < report.Changes[j].Code
+	})
+	return report
+}
+
+func newChange(a *domain.Activity) Change {
+	return Change{Code: a.Code, CourseCode: a.CourseCode, CourseName: a.CourseName, Type: a.Type}
+}
+
+// Counts cuenta los cambios del reporte por tipo
+func (r Report) Counts() Counts {
+	var n Counts
+	for i := range r.Changes {
+		c := &r.Changes[i]
+		switch {
+		case c.Added:
+			n.Added++
+		case c.Removed:
+			n.Removed++
+		default:
+			n.Changed++
+			if c.BlockChanged() {
+				n.Block++
+			}
+			if c.RoomChanged() {
+				n.Room++
+			}
+			if c.TeachersChanged() {
+				n.Teachers++
+			}
+		}
+	}
+	return n
+}
+
+// ByCourse agrupa los cambios por curso ("CBE2000 probabilidades y estadística")
+func (r Report) ByCourse() []Group {
+	return r.group(func(c *Change) []string {
+		return []string{strings.TrimSpace(c.CourseCode + " " + c.CourseName)}
+	})
+}
+
+// ByTeacher agrupa los cambios por profesor afectado, para avisar a cada uno de lo que cambió en su horario
+func (r Report) ByTeacher() []Group {
+	return r.group((*Change).Teachers)
+}
+
+// ByRoom agrupa los cambios por sala afectada (la que se libera y la que se ocupa)
+func (r Report) ByRoom() []Group {
+	return r.group((*Change).Rooms)
+}
+
+// group agrupa los cambios según las claves de cada uno; los grupos quedan ordenados por nombre
+func (r Report) group(keys func(*Change) []string) []Group {
+	index := make(map[string]int)
+	var groups []Group
+	for i := range r.Changes {
+		c := &r.Changes[i]
+		for _, k := range keys(c) {
+			g, ok := index[k]
+			if !ok {
+				g = len(groups)
+				index[k] = g
+				groups = append(groups, Group{Name: k})
+			}
+			groups[g].Changes = append(groups[g].Changes, c)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+func timeLabel(block, duration int) string {
+	if block < 0 {
+		return "sin bloque"
+	}
+	if block >= domain.TotalBlocks {
+		return fmt.Sprintf("bloque %d", block)
+	}
+	return domain.DayNames[block/domain.BlocksPerDay] + " " + domain.BlockTimeRange(block, duration)
+}
+
+func roomLabel(room string) string {
+	if room == "" {
+		return "sin sala"
+	}
+	return room
+}
+
+func teachersLabel(names []string) string {
+	if len(names) == 0 {
+		return "sin profesor"
+	}
+	return strings.Join(names, ", ")
+}
+
+func placement(block, duration int, room string) string {
+	if block < 0 {
+		return "sin programar"
+	}
+	return timeLabel(block, duration) + " en " + roomLabel(room)
+}
+
+// sameNames compara dos listas de profesores sin importar el orden
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func union(a, b []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
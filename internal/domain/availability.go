@@ -0,0 +1,113 @@
+package domain
+
+import "strings"
+
+// PreferenceLevel indica cuánto desea (positivo) o rechaza (negativo) un profesor hacer clases en un rango de bloques
+type PreferenceLevel int
+
+const (
+	StronglyUndesired PreferenceLevel = -2 // Evitar en lo posible
+	Undesired         PreferenceLevel = -1 // Preferible no
+	Neutral           PreferenceLevel = 0
+	Preferred         PreferenceLevel = 1 // Preferible sí
+	StronglyPreferred PreferenceLevel = 2 // Muy deseado
+)
+
+// SlotRange es un rango de slots dentro de un día, ambos extremos inclusive (0 = primer bloque del día)
+type SlotRange struct {
+	From int
+	To   int
+}
+
+// Contains verifica si el slot está dentro del rango
+func (r SlotRange) Contains(slot int) bool {
+	return slot >= r.From && slot <= r.To
+}
+
+// SlotPreference es un rango de slots con un nivel de preferencia
+type SlotPreference struct {
+	SlotRange
+	Level PreferenceLevel
+}
+
+// Availability define la disponibilidad semanal de un profesor, indexada por día (0 = lunes).
+// Unavailable son rangos donde NO puede hacer clases. Si AvailableOnly tiene al menos un rango,
+// el profesor solo puede hacer clases dentro de esos rangos (los días sin rangos quedan bloqueados).
+// Preferences son preferencias blandas que no restringen la asignación.
+type Availability struct {
+	Unavailable   map[int][]SlotRange
+	AvailableOnly map[int][]SlotRange
+	Preferences   map[int][]SlotPreference
+}
+
+// IsAvailable verifica si se puede hacer clases en todos los bloques desde block hasta block+duration-1
+func (av *Availability) IsAvailable(block, duration int) bool {
+	if block < 0 {
+		return false
+	}
+	if duration < 1 {
+		duration = 1
+	}
+
+	hasWhitelist := len(av.AvailableOnly) > 0
+
+	for b := block; b < block+duration; b++ {
+		day := b / BlocksPerDay
+		slot := b % BlocksPerDay
+
+		for _, r := range av.Unavailable[day] {
+			if r.Contains(slot) {
+				return false
+			}
+		}
+
+		if hasWhitelist {
+			allowed := false
+			for _, r := range av.AvailableOnly[day] {
+				if r.Contains(slot) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Preference retorna el nivel de preferencia de un bloque, si hay rangos que se solapan se usa el de mayor magnitud
+func (av *Availability) Preference(block int) PreferenceLevel {
+	if block < 0 {
+		return Neutral
+	}
+	day := block / BlocksPerDay
+	slot := block % BlocksPerDay
+
+	level := Neutral
+	for _, p := range av.Preferences[day] {
+		if p.Contains(slot) && abs(int(p.Level)) > abs(int(level)) {
+			level = p.Level
+		}
+	}
+	return level
+}
+
+// AllDaysKey es la llave que aplica un rango a todos los días de la semana
+const AllDaysKey = "todos"
+
+// accentReplacer elimina tildes para comparar nombres de días
+var accentReplacer = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u")
+
+// ParseDay convierte un nombre de día (sin importar mayúsculas ni tildes) a su índice 0-based en la grilla activa
+func ParseDay(name string) (int, error) {
+	return Grid.DayIndex(name)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
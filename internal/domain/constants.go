@@ -22,32 +22,48 @@ const (
 	RoomLab       RoomType = "LABORATORIO"
 )
 
-const (
-	// definición de bloques horarios
+// Duración clases (80 minutos)
+const BlockDuration = 80 * time.Minute
+
+// definición de bloques horarios, se recalculan con SetTimeGrid a partir de la grilla activa
+var (
 	BlocksPerDay = 7
 	DaysPerWeek  = 5
 	TotalBlocks  = BlocksPerDay * DaysPerWeek // 35 bloques
-
-	// Duración clases (80 minutos)
-	BlockDuration = 80 * time.Minute
-
-	// miercoles 11:30-12:50 horario protegido
-	ProtectedWednesdayBlock = 2*BlocksPerDay + 2 // = 16
 )
 
-// IsProtectedBlock verifica si un bloque es el horario protegido
+// IsProtectedBlock verifica si un bloque está bloqueado para toda la institución (p.ej. miércoles 11:30-12:50)
 func IsProtectedBlock(block int) bool {
-	return block == ProtectedWednesdayBlock
+	for _, p := range Grid.Blocked {
+		if p.AppliesToAll() && p.Contains(block) {
+			return true
+		}
+	}
+	return false
 }
 
-// OccupiesProtectedBlock verifica si una actividad ocupa el bloque protegido
+// OccupiesProtectedBlock verifica si una actividad ocupa algún bloque bloqueado para toda la institución
 func OccupiesProtectedBlock(startBlock, duration int) bool {
+	return OccupiesBlockedPeriod(startBlock, duration, nil)
+}
+
+// OccupiesBlockedPeriod verifica si una actividad ocupa algún periodo bloqueado para la institución
+// o para alguna de las carreras indicadas
+func OccupiesBlockedPeriod(startBlock, duration int, majors []string) bool {
 	if duration < 1 {
 		duration = 1
 	}
-	endBlock := startBlock + duration - 1
-
-	return startBlock <= ProtectedWednesdayBlock && ProtectedWednesdayBlock <= endBlock
+	for _, p := range Grid.Blocked {
+		if !p.AppliesToAny(majors) {
+			continue
+		}
+		for b := startBlock; b < startBlock+duration; b++ {
+			if p.Contains(b) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // valores de penalización para SA
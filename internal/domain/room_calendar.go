@@ -0,0 +1,77 @@
+package domain
+
+// RoomCalendar registra qué actividad ocupa cada sala en cada bloque de la semana (sala × bloque).
+// Lo comparten el scheduler, SA y el validador, para que todos consideren ocupada la sala en cada bloque
+// que usa una actividad de varios bloques. Debe crearse después de SetTimeGrid.
+type RoomCalendar struct {
+	slots map[string][]*Activity // sala -> ocupante por bloque, de largo TotalBlocks
+}
+
+// NewRoomCalendar crea un calendario vacío
+func NewRoomCalendar() *RoomCalendar {
+	return &RoomCalendar{slots: make(map[string][]*Activity)}
+}
+
+// BuildRoomCalendar crea el calendario con las actividades programadas que tienen sala
+func BuildRoomCalendar(activities []Activity) *RoomCalendar {
+	c := NewRoomCalendar()
+	for i := range activities {
+		a := &activities[i]
+		if a.Block >= 0 && a.Room != "" {
+			c.Reserve(a, a.Room, a.Block)
+		}
+	}
+	return c
+}
+
+// At retorna la actividad que ocupa la sala en el bloque, o nil si está libre
+func (c *RoomCalendar) At(room string, block int) *Activity {
+	if block < 0 || block >= TotalBlocks {
+		return nil
+	}
+	if s := c.slots[room]; s != nil {
+		return s[block]
+	}
+	return nil
+}
+
+// IsFree verifica si la sala está libre en los bloques que ocuparía la actividad comenzando en block.
+// Los bloques que ocupa la misma actividad cuentan como libres, así se puede evaluar moverla dentro de la sala.
+func (c *RoomCalendar) IsFree(a *Activity, room string, block int) bool {
+	for b := block; b < block+a.Span(); b++ {
+		if other := c.At(room, b); other != nil && other.ID != a.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// Reserve marca la sala como ocupada por la actividad en cada bloque que ocupa comenzando en block
+func (c *RoomCalendar) Reserve(a *Activity, room string, block int) {
+	if room == "" {
+		return
+	}
+	s := c.slots[room]
+	if s == nil {
+		s = make([]*Activity, TotalBlocks)
+		c.slots[room] = s
+	}
+	for b := block; b < block+a.Span(); b++ {
+		if b >= 0 && b < TotalBlocks {
+			s[b] = a
+		}
+	}
+}
+
+// Release libera los bloques de la sala que ocupa la actividad comenzando en block
+func (c *RoomCalendar) Release(a *Activity, room string, block int) {
+	s := c.slots[room]
+	if s == nil {
+		return
+	}
+	for b := block; b < block+a.Span(); b++ {
+		if b >= 0 && b < TotalBlocks && s[b] != nil && s[b].ID == a.ID {
+			s[b] = nil
+		}
+	}
+}
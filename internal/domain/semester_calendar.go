@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Holiday es un día o rango de días sin clases (feriado, semana de receso)
+type Holiday struct {
+	Name string
+	From time.Time // Primer día (inclusive)
+	To   time.Time // Último día (inclusive)
+}
+
+// SemesterCalendar define las fechas del semestre en que se repite el horario semanal
+type SemesterCalendar struct {
+	Start    time.Time // Primer día de clases (inclusive)
+	End      time.Time // Último día de clases (inclusive)
+	TimeZone string    // Zona horaria IANA de las horas de la grilla ("America/Santiago"), vacío = hora local
+	Holidays []Holiday
+}
+
+// HolidayOn retorna el feriado que incluye la fecha, si existe
+func (c SemesterCalendar) HolidayOn(date time.Time) (Holiday, bool) {
+	for _, h := range c.Holidays {
+		if !date.Before(h.From) && !date.After(h.To) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// weekdays reconoce los nombres de días en español e inglés, sin tildes
+var weekdays = map[string]time.Weekday{
+	"lunes": time.Monday, "martes": time.Tuesday, "miercoles": time.Wednesday, "jueves": time.Thursday,
+	"viernes": time.Friday, "sabado": time.Saturday, "domingo": time.Sunday,
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+}
+
+// Weekday retorna el día de la semana de un día de la grilla activa, según su nombre
+func Weekday(day int) (time.Weekday, error) {
+	if day < 0 || day >= len(Grid.Days) {
+		return 0, fmt.Errorf("día %d fuera de la grilla", day)
+	}
+	name := Grid.Days[day].Name
+	if w, ok := weekdays[accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))]; ok {
+		return w, nil
+	}
+	return 0, fmt.Errorf("el día %q de la grilla no es un día de la semana", name)
+}
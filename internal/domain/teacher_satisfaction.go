@@ -0,0 +1,71 @@
+package domain
+
+import "sort"
+
+// TeacherSatisfaction resume qué tan bien se respetaron las preferencias blandas de un profesor
+type TeacherSatisfaction struct {
+	Teacher         string
+	TotalBlocks     int     // Bloques de clase asignados
+	PreferredBlocks int     // Bloques en rangos preferidos
+	UndesiredBlocks int     // Bloques en rangos no deseados
+	Satisfaction    float64 // Porcentaje de bloques que no caen en rangos no deseados
+}
+
+// CalculateTeacherSatisfaction calcula la satisfacción de cada profesor que declaró preferencias
+func CalculateTeacherSatisfaction(activities []Activity, teachers []Teacher) []TeacherSatisfaction {
+	teacherIndex := make(map[string]*Teacher, len(teachers))
+	for i := range teachers {
+		teacherIndex[teachers[i].Name] = &teachers[i]
+	}
+	stats := make(map[string]*TeacherSatisfaction)
+
+	for i := range activities {
+		a := &activities[i]
+		for _, name := range a.TeacherNames {
+			t, ok := teacherIndex[name]
+			if !ok || len(t.Availability.Preferences) == 0 {
+				continue
+			}
+			s := stats[name]
+			if s == nil {
+				s = &TeacherSatisfaction{Teacher: name}
+				stats[name] = s
+			}
+			for _, b := range a.BlocksOccupied() {
+				s.TotalBlocks++
+				level := t.Availability.Preference(b)
+				if level > Neutral {
+					s.PreferredBlocks++
+				} else if level < Neutral {
+					s.UndesiredBlocks++
+				}
+			}
+		}
+	}
+
+	result := make([]TeacherSatisfaction, 0, len(stats))
+	for _, s := range stats {
+		s.Satisfaction = 100.0
+		if s.TotalBlocks > 0 {
+			s.Satisfaction = float64(s.TotalBlocks-s.UndesiredBlocks) / float64(s.TotalBlocks) * 100.0
+		}
+		result = append(result, *s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Teacher < result[j].Teacher
+	})
+	return result
+}
+
+// AverageSatisfaction retorna el promedio de satisfacción de los profesores con preferencias
+func AverageSatisfaction(satisfaction []TeacherSatisfaction) float64 {
+	if len(satisfaction) == 0 {
+		return 100.0
+	}
+	total := 0.0
+	for _, s := range satisfaction {
+		total += s.Satisfaction
+	}
+	return total / float64(len(satisfaction))
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimeSlot es un bloque horario dentro del día
+type TimeSlot struct {
+	Start string // Hora de inicio ("08:30")
+	End   string // Hora de término ("09:50")
+}
+
+// Label retorna el rango horario del slot ("08:30-09:50")
+func (s TimeSlot) Label() string {
+	return s.Start + "-" + s.End
+}
+
+// GridDay es un día de la grilla semanal
+type GridDay struct {
+	Name  string
+	Slots int // Cantidad de slots utilizables desde el inicio del día (0 = todos), p.ej. sábado solo en la mañana
+}
+
+// BlockedPeriod es un rango de slots de un día donde no se pueden programar clases
+// (horario protegido, consejo de facultad, seminario de una carrera).
+type BlockedPeriod struct {
+	Name   string
+	Day    int
+	From   int      // Primer slot bloqueado (inclusive)
+	To     int      // Último slot bloqueado (inclusive)
+	Majors []string // Carreras afectadas según PlanLocation ("EIT"), vacío = toda la institución
+}
+
+// Contains verifica si el bloque cae dentro del periodo
+func (p BlockedPeriod) Contains(block int) bool {
+	if block < 0 || block/BlocksPerDay != p.Day {
+		return false
+	}
+	slot := block % BlocksPerDay
+	return slot >= p.From && slot <= p.To
+}
+
+// AppliesToAll indica si el periodo aplica a toda la institución
+func (p BlockedPeriod) AppliesToAll() bool {
+	return len(p.Majors) == 0
+}
+
+// AppliesToAny indica si el periodo aplica a la institución o a alguna de las carreras dadas
+func (p BlockedPeriod) AppliesToAny(majors []string) bool {
+	if p.AppliesToAll() {
+		return true
+	}
+	for _, m := range p.Majors {
+		for _, other := range majors {
+			if m == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TimeGrid define la grilla semanal: días, slots horarios y periodos bloqueados. Un bloque es día*BlocksPerDay + slot.
+type TimeGrid struct {
+	Days    []GridDay
+	Slots   []TimeSlot
+	Blocked []BlockedPeriod
+}
+
+// DayIndex convierte un nombre de día (sin importar mayúsculas ni tildes) a su índice en la grilla
+func (g TimeGrid) DayIndex(name string) (int, error) {
+	normalized := accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	for i, d := range g.Days {
+		if accentReplacer.Replace(strings.ToLower(d.Name)) == normalized {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("día desconocido %q", name)
+}
+
+// DefaultTimeGrid retorna la grilla de la FIC: lunes a viernes, 7 bloques de 80 minutos
+func DefaultTimeGrid() TimeGrid {
+	return TimeGrid{
+		Days: []GridDay{
+			{Name: "Lunes"},
+			{Name: "Martes"},
+			{Name: "Miércoles"},
+			{Name: "Jueves"},
+			{Name: "Viernes"},
+		},
+		Slots: []TimeSlot{
+			{Start: "08:30", End: "09:50"},
+			{Start: "10:00", End: "11:20"},
+			{Start: "11:30", End: "12:50"},
+			{Start: "13:00", End: "14:20"},
+			{Start: "14:30", End: "15:50"},
+			{Start: "16:00", End: "17:20"},
+			{Start: "17:25", End: "18:45"},
+		},
+		Blocked: []BlockedPeriod{
+			{Name: "Horario protegido", Day: 2, From: 2, To: 2},
+		},
+	}
+}
+
+// Grid es la grilla activa, se cambia con SetTimeGrid
+var Grid = DefaultTimeGrid()
+
+// DayNames son los nombres de los días de la grilla activa, en orden
+var DayNames = dayNames(Grid)
+
+// WednesdayDay es el índice del miércoles en la grilla activa (-1 si no existe)
+var WednesdayDay = 2
+
+// SetTimeGrid reemplaza la grilla activa y recalcula las dimensiones derivadas.
+// Debe llamarse antes de cargar datos que dependan de la grilla (disponibilidad de profesores).
+func SetTimeGrid(g TimeGrid) {
+	Grid = g
+	BlocksPerDay = len(g.Slots)
+	DaysPerWeek = len(g.Days)
+	TotalBlocks = BlocksPerDay * DaysPerWeek
+	DayNames = dayNames(g)
+
+	WednesdayDay = -1
+	if day, err := ParseDay("miércoles"); err == nil {
+		WednesdayDay = day
+	}
+}
+
+func dayNames(g TimeGrid) []string {
+	names := make([]string, len(g.Days))
+	for i, d := range g.Days {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// SlotsInDay retorna la cantidad de slots utilizables de un día
+func SlotsInDay(day int) int {
+	if day < 0 || day >= len(Grid.Days) {
+		return 0
+	}
+	if n := Grid.Days[day].Slots; n > 0 && n < BlocksPerDay {
+		return n
+	}
+	return BlocksPerDay
+}
+
+// IsUsableBlock verifica si el bloque existe en la grilla y su slot es utilizable ese día
+func IsUsableBlock(block int) bool {
+	if block < 0 || block >= TotalBlocks {
+		return false
+	}
+	return block%BlocksPerDay < SlotsInDay(block/BlocksPerDay)
+}
+
+// FitsInDay verifica que una actividad de duration bloques que comienza en block termine el mismo día
+// y solo use slots utilizables
+func FitsInDay(block, duration int) bool {
+	if duration < 1 {
+		duration = 1
+	}
+	if !IsUsableBlock(block) {
+		return false
+	}
+	day := block / BlocksPerDay
+	slot := block % BlocksPerDay
+	return slot+duration <= SlotsInDay(day)
+}
+
+// BlockTimeRange retorna el rango horario de una actividad ("08:30-11:20"), vacío si el bloque no existe
+func BlockTimeRange(block, duration int) string {
+	if block < 0 || block >= TotalBlocks {
+		return ""
+	}
+	if duration < 1 {
+		duration = 1
+	}
+	slot := block % BlocksPerDay
+	endSlot := slot + duration - 1
+	if endSlot >= BlocksPerDay {
+		endSlot = BlocksPerDay - 1
+	}
+	return Grid.Slots[slot].Start + "-" + Grid.Slots[endSlot].End
+}
+
+// BlockLabel retorna una descripción legible del bloque ("Lunes 08:30-09:50")
+func BlockLabel(block int) string {
+	if block < 0 || block >= TotalBlocks {
+		return fmt.Sprintf("bloque %d", block)
+	}
+	return DayNames[block/BlocksPerDay] + " " + Grid.Slots[block%BlocksPerDay].Label()
+}
@@ -23,9 +23,14 @@ type Distribution struct {
 
 // Teacher representa a un profesor
 type Teacher struct {
-	ID         int
-	Name       string
-	BusyBlocks []int // Bloques (0-34) donde NO puede hacer clases
+	ID           int
+	Name         string
+	Availability Availability // Disponibilidad semanal por día
+}
+
+// IsAvailable verifica si el profesor puede hacer una clase que comienza en block y dura duration bloques
+func (t *Teacher) IsAvailable(block, duration int) bool {
+	return t.Availability.IsAvailable(block, duration)
 }
 
 // Room representa un espacio físico.
@@ -145,6 +150,14 @@ func (a *Activity) SharesSection(other *Activity) bool {
 	return false
 }
 
+// Span retorna la cantidad de bloques que ocupa la actividad, al menos uno
+func (a *Activity) Span() int {
+	if a.Duration < 1 {
+		return 1
+	}
+	return a.Duration
+}
+
 // BlocksOccupied retorna la lista de bloques que ocupa la actividad, para actividades de más de un bloque
 func (a *Activity) BlocksOccupied() []int {
 	if a.Block < 0 {
@@ -0,0 +1,86 @@
+package domain
+
+// ObjectiveWeights contiene el peso de cada término de la función objetivo de SA.
+// Un peso es un costo por ocurrencia; un valor negativo es un bono.
+type ObjectiveWeights struct {
+	Hard float64 // cada violación dura que queda tras reparar actividades DUD
+
+	Mirror      float64 // cátedra hermana en distinto slot (espejo)
+	SiblingRoom float64 // cátedra hermana en distinta sala
+
+	// Separación de días entre las dos cátedras de un grupo de 2
+	DaySeparation0     float64 // mismo día
+	DaySeparation1     float64 // días consecutivos
+	DaySeparation2     float64 // un día entre medio
+	DaySeparation3     float64 // separación ideal (Lun-Jue, Mar-Vie)
+	DaySeparationOther float64 // cualquier otra separación
+
+	// Separación de días en grupos de 3 o más cátedras
+	MultiCATSameDay float64 // dos cátedras el mismo día
+	MultiCATNextDay float64 // dos cátedras en días consecutivos
+
+	CATSameDayAsAY    float64 // cátedra el mismo día que una ayudantía de su grupo
+	AYNotWednesday    float64 // ayudantía fuera del miércoles
+	PrereqSameBlock   float64 // curso y prerrequisito en el mismo bloque (no comparten alumnos)
+	TeacherPreference float64 // por nivel de preferencia de profesor y bloque
+
+	// Re-optimización de un horario publicado: solo se aplican a las actividades que tienen un bloque y sala originales
+	MovedBlock float64 // actividad fuera de su bloque original
+	MovedRoom  float64 // actividad fuera de su sala original
+}
+
+// DefaultObjectiveWeights retorna los pesos usados hasta ahora. Los que equivalen a un nivel de penalización
+// lo indican en el comentario.
+func DefaultObjectiveWeights() ObjectiveWeights {
+	return ObjectiveWeights{
+		Hard: 100000, // PenaltyHard
+
+		Mirror:      50,
+		SiblingRoom: 30,
+
+		DaySeparation0:     60,
+		DaySeparation1:     25,
+		DaySeparation2:     0,
+		DaySeparation3:     -20,
+		DaySeparationOther: 10, // PenaltySoft
+
+		MultiCATSameDay: 80,
+		MultiCATNextDay: 15,
+
+		CATSameDayAsAY:    35,
+		AYNotWednesday:    10, // PenaltySoft
+		PrereqSameBlock:   -15,
+		TeacherPreference: 15,
+
+		MovedBlock: 1000, // PenaltyMedium
+		MovedRoom:  300,
+	}
+}
+
+// TwoCATSeparation retorna el costo de separar dos cátedras hermanas por days días
+func (w *ObjectiveWeights) TwoCATSeparation(days int) float64 {
+	switch days {
+	case 0:
+		return w.DaySeparation0
+	case 1:
+		return w.DaySeparation1
+	case 2:
+		return w.DaySeparation2
+	case 3:
+		return w.DaySeparation3
+	default:
+		return w.DaySeparationOther
+	}
+}
+
+// MultiCATSeparation retorna el costo de separar por days días dos cátedras de un grupo de 3 o más
+func (w *ObjectiveWeights) MultiCATSeparation(days int) float64 {
+	switch days {
+	case 0:
+		return w.MultiCATSameDay
+	case 1:
+		return w.MultiCATNextDay
+	default:
+		return 0
+	}
+}
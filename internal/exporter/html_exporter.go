@@ -0,0 +1,405 @@
+package exporter
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// HTMLSummary cuenta las páginas de horario escritas por ExportHTML
+type HTMLSummary struct {
+	Rooms     int
+	Teachers  int
+	Semesters int
+}
+
+// Total retorna la cantidad de páginas de horario escritas (sin contar el índice)
+func (s HTMLSummary) Total() int {
+	return s.Rooms + s.Teachers + s.Semesters
+}
+
+// ExportHTML escribe en dir páginas HTML estáticas para imprimir (o guardar como PDF desde el navegador): una grilla
+// semanal por cada sala de rooms, cada profesor (de teachers y de las actividades) y cada carrera-semestre de
+// planLocations, en los subdirectorios salas, profesores y carreras, más un index.html con los enlaces. Las páginas
+// no dependen de archivos externos ni del servidor de cmd/web.
+func ExportHTML(activities []domain.Activity, rooms []domain.Room, teachers []domain.Teacher, planLocations map[string]map[string]int, dir string) (HTMLSummary, error) {
+	var summary HTMLSummary
+
+	byRoom := make(map[string][]*domain.Activity)
+	for _, r := range rooms {
+		byRoom[r.Code] = nil
+	}
+	byTeacher := make(map[string][]*domain.Activity)
+	for _, t := range teachers {
+		byTeacher[t.Name] = nil
+	}
+	bySemester := make(map[string][]*domain.Activity)
+	semesterMajor := make(map[string]string)
+	for _, locations := range planLocations {
+		for major, semester := range locations {
+			key := fmt.Sprintf("%s semestre %d", major, semester)
+			bySemester[key] = nil
+			semesterMajor[key] = major
+		}
+	}
+
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 || a.Block >= domain.TotalBlocks {
+			continue
+		}
+		if a.Room != "" {
+			byRoom[a.Room] = append(byRoom[a.Room], a)
+		}
+		for _, t := range a.TeacherNames {
+			byTeacher[t] = append(byTeacher[t], a)
+		}
+		for major, semester := range planLocations[a.CourseCode] {
+			key := fmt.Sprintf("%s semestre %d", major, semester)
+			bySemester[key] = append(bySemester[key], a)
+		}
+	}
+
+	capacity := make(map[string]domain.Room, len(rooms))
+	for _, r := range rooms {
+		capacity[r.Code] = r
+	}
+
+	generated := time.Now().Format("02-01-2006 15:04")
+	groups := []struct {
+		subdir string
+		title  string
+		view   string
+		byName map[string][]*domain.Activity
+		count  *int
+	}{
+		{"salas", "Salas", "sala", byRoom, &summary.Rooms},
+		{"profesores", "Profesores", "profesor", byTeacher, &summary.Teachers},
+		{"carreras", "Carreras y semestres", "carrera", bySemester, &summary.Semesters},
+	}
+
+	index := htmlIndex{Generated: generated}
+	for _, g := range groups {
+		path := filepath.Join(dir, g.subdir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return summary, err
+		}
+
+		names := make([]string, 0, len(g.byName))
+		for name := range g.byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		files := uniqueFileNames(names)
+
+		section := htmlIndexSection{Title: g.title}
+		for _, name := range names {
+			page := htmlPage{Generated: generated, Index: "../index.html"}
+			var majors []string
+			switch g.view {
+			case "sala":
+				page.Title = "Sala " + name
+				if r, ok := capacity[name]; ok {
+					page.Subtitle = fmt.Sprintf("%s, capacidad %d", r.Type, r.Capacity)
+				} else {
+					page.Subtitle = "no está en rooms.csv"
+				}
+			case "profesor":
+				page.Title = name
+				page.Subtitle = "Profesor"
+			case "carrera":
+				page.Title = name
+				page.Subtitle = "Plan de estudios (PlanLocation)"
+				majors = []string{semesterMajor[name]}
+			}
+			page.Days, page.Rows = buildWeekGrid(g.byName[name], g.view, majors)
+
+			file := g.subdir + "/" + files[name] + ".html"
+			if err := writeHTMLFile(filepath.Join(dir, file), htmlPageTemplate, page); err != nil {
+				return summary, err
+			}
+			section.Links = append(section.Links, htmlIndexLink{Name: name, File: file, Activities: len(g.byName[name])})
+			*g.count++
+		}
+		index.Sections = append(index.Sections, section)
+	}
+
+	if err := writeHTMLFile(filepath.Join(dir, "index.html"), htmlIndexTemplate, index); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// uniqueFileNames asigna a cada nombre un slug de archivo; los nombres con el mismo slug (p. ej. solo difieren en
+// tildes) reciben un sufijo numérico para no sobrescribirse
+func uniqueFileNames(names []string) map[string]string {
+	files := make(map[string]string, len(names))
+	used := make(map[string]int)
+	for _, name := range names {
+		file := slug(name)
+		used[file]++
+		if used[file] > 1 {
+			file += fmt.Sprintf("-%d", used[file])
+		}
+		files[name] = file
+	}
+	return files
+}
+
+func writeHTMLFile(path string, tmpl *template.Template, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// htmlCell es una celda de la grilla: vacía, fuera del día, bloqueada o con actividades. Las actividades que se
+// traslapan en el tiempo comparten una celda que abarca (rowspan) todos los slots que ocupan.
+type htmlCell struct {
+	Span    int
+	Class   string // "", "closed", "blocked" o "busy"
+	Label   string // Nombre del periodo bloqueado
+	Entries []htmlEntry
+}
+
+// htmlEntry es una actividad dentro de una celda
+type htmlEntry struct {
+	Class   string // type-catedra, type-ayudantia o type-laboratorio
+	Title   string // "CBE2000 CAT secc. 1, 2"
+	Time    string
+	Name    string
+	Details []string
+}
+
+// htmlRow es una fila de la grilla: un slot con sus celdas, sin las que quedan cubiertas por un rowspan
+type htmlRow struct {
+	Slot  string
+	Cells []htmlCell
+}
+
+// buildWeekGrid arma la grilla días x slots de una página. view indica qué dato ya está en el título y no se repite
+// en cada actividad ("sala", "profesor" o "carrera"); majors limita los periodos bloqueados que se muestran.
+func buildWeekGrid(activities []*domain.Activity, view string, majors []string) ([]string, []htmlRow) {
+	rows := make([]htmlRow, domain.BlocksPerDay)
+	for s := range rows {
+		rows[s].Slot = domain.Grid.Slots[s].Label()
+	}
+
+	byDay := make([][]*domain.Activity, domain.DaysPerWeek)
+	for _, a := range activities {
+		day := a.Block / domain.BlocksPerDay
+		byDay[day] = append(byDay[day], a)
+	}
+
+	for day := 0; day < domain.DaysPerWeek; day++ {
+		dayActivities := byDay[day]
+		sort.Slice(dayActivities, func(i, j int) bool {
+			if dayActivities[i].Block != dayActivities[j].Block {
+				return dayActivities[i].Block < dayActivities[j].Block
+			}
+			return dayActivities[i].Code < dayActivities[j].Code
+		})
+
+		next := 0 // siguiente actividad del día por ubicar
+		for slot := 0; slot < domain.BlocksPerDay; {
+			block := day*domain.BlocksPerDay + slot
+			if next < len(dayActivities) && dayActivities[next].Block%domain.BlocksPerDay == slot {
+				// Agrupar las actividades que se traslapan con las ya agrupadas
+				cell := htmlCell{Class: "busy"}
+				end := slot
+				for next < len(dayActivities) && dayActivities[next].Block%domain.BlocksPerDay <= end {
+					a := dayActivities[next]
+					if last := a.Block%domain.BlocksPerDay + a.Span() - 1; last > end {
+						end = last
+					}
+					cell.Entries = append(cell.Entries, newHTMLEntry(a, view))
+					next++
+				}
+				if end >= domain.BlocksPerDay {
+					end = domain.BlocksPerDay - 1
+				}
+				cell.Span = end - slot + 1
+				rows[slot].Cells = append(rows[slot].Cells, cell)
+				slot = end + 1
+				continue
+			}
+
+			cell := htmlCell{Span: 1}
+			switch {
+			case slot >= domain.SlotsInDay(day):
+				cell.Class = "closed"
+			default:
+				for _, p := range domain.Grid.Blocked {
+					if p.Contains(block) && p.AppliesToAny(majors) {
+						cell.Class = "blocked"
+						cell.Label = p.Name
+						break
+					}
+				}
+			}
+			rows[slot].Cells = append(rows[slot].Cells, cell)
+			slot++
+		}
+	}
+	return domain.DayNames, rows
+}
+
+func newHTMLEntry(a *domain.Activity, view string) htmlEntry {
+	typeLabel := map[domain.EventCategory]string{domain.CAT: "CAT", domain.AY: "AYU", domain.LAB: "LAB"}[a.Type]
+	e := htmlEntry{
+		Class: "type-" + strings.ToLower(string(a.Type)),
+		Title: fmt.Sprintf("%s %s secc. %s", a.CourseCode, typeLabel, joinInts(a.Sections)),
+		Time:  domain.BlockTimeRange(a.Block, a.Duration),
+		Name:  a.CourseName,
+	}
+	if view != "sala" && a.Room != "" {
+		e.Details = append(e.Details, "Sala "+a.Room)
+	}
+	if view != "profesor" && len(a.TeacherNames) > 0 {
+		e.Details = append(e.Details, strings.Join(a.TeacherNames, ", "))
+	}
+	return e
+}
+
+type htmlPage struct {
+	Title     string
+	Subtitle  string
+	Generated string
+	Index     string
+	Days      []string
+	Rows      []htmlRow
+}
+
+type htmlIndex struct {
+	Generated string
+	Sections  []htmlIndexSection
+}
+
+type htmlIndexSection struct {
+	Title string
+	Links []htmlIndexLink
+}
+
+type htmlIndexLink struct {
+	Name       string
+	File       string
+	Activities int
+}
+
+// htmlStyles son los estilos comunes de las páginas, con los colores por tipo del visualizador web
+const htmlStyles = `
+* { box-sizing: border-box; margin: 0; padding: 0; }
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; color: #1e293b; padding: 1.5rem; }
+header { display: flex; justify-content: space-between; align-items: baseline; margin-bottom: 1rem; }
+h1 { font-size: 1.4rem; }
+h2 { font-size: 1.1rem; margin: 1.5rem 0 0.5rem; }
+.subtitle, .generated { color: #64748b; font-size: 0.8rem; }
+nav a, nav button { font-size: 0.8rem; margin-left: 0.75rem; color: #2563eb; background: none; border: none; cursor: pointer; }
+table.grid { width: 100%; border-collapse: collapse; table-layout: fixed; }
+.grid th, .grid td { border: 1px solid #cbd5e1; padding: 0.25rem; vertical-align: top; font-size: 0.7rem; }
+.grid th { background: #1e293b; color: white; font-weight: 500; }
+.grid th.slot { width: 6.5rem; }
+.grid td.closed { background: repeating-linear-gradient(45deg, #f1f5f9, #f1f5f9 4px, #e2e8f0 4px, #e2e8f0 8px); }
+.grid td.blocked { background: #f1f5f9; color: #64748b; text-align: center; vertical-align: middle; font-style: italic; }
+.entry { border-left: 3px solid; border-radius: 3px; padding: 0.2rem 0.3rem; margin-bottom: 0.2rem; }
+.entry:last-child { margin-bottom: 0; }
+.entry .title { font-weight: 600; }
+.entry .time, .entry .detail { color: #475569; }
+.type-catedra { background: #dbeafe; border-color: #2563eb; }
+.type-ayudantia { background: #d1fae5; border-color: #10b981; }
+.type-laboratorio { background: #fef3c7; border-color: #f59e0b; }
+ul.links { columns: 4 14rem; list-style: none; font-size: 0.85rem; }
+ul.links a { color: #2563eb; text-decoration: none; }
+ul.links .count { color: #64748b; }
+@page { size: A4 landscape; margin: 1cm; }
+@media print {
+  body { padding: 0; }
+  nav { display: none; }
+  .entry, .grid th, .grid td { -webkit-print-color-adjust: exact; print-color-adjust: exact; }
+  tr { break-inside: avoid; }
+}
+`
+
+var htmlPageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>` + htmlStyles + `</style>
+</head>
+<body>
+<header>
+  <div>
+    <h1>{{.Title}}</h1>
+    <div class="subtitle">{{.Subtitle}}</div>
+  </div>
+  <nav><a href="{{.Index}}">Índice</a><button onclick="window.print()">Imprimir</button></nav>
+</header>
+<table class="grid">
+  <thead>
+    <tr><th class="slot">Horario</th>{{range .Days}}<th>{{.}}</th>{{end}}</tr>
+  </thead>
+  <tbody>
+{{- range .Rows}}
+    <tr><th class="slot">{{.Slot}}</th>
+    {{- range .Cells}}
+      <td{{if gt .Span 1}} rowspan="{{.Span}}"{{end}}{{if .Class}} class="{{.Class}}"{{end}}>
+      {{- if .Label}}{{.Label}}{{end}}
+      {{- range .Entries}}
+        <div class="entry {{.Class}}">
+          <div class="title">{{.Title}}</div>
+          <div class="time">{{.Time}}</div>
+          <div class="name">{{.Name}}</div>
+          {{- range .Details}}
+          <div class="detail">{{.}}</div>
+          {{- end}}
+        </div>
+      {{- end}}
+      </td>
+    {{- end}}
+    </tr>
+{{- end}}
+  </tbody>
+</table>
+<p class="generated">Generado el {{.Generated}}</p>
+</body>
+</html>
+`))
+
+var htmlIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Horarios</title>
+<style>` + htmlStyles + `</style>
+</head>
+<body>
+<header>
+  <div>
+    <h1>Horarios</h1>
+    <div class="subtitle">Grillas semanales para imprimir</div>
+  </div>
+</header>
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+<ul class="links">
+  {{- range .Links}}
+  <li><a href="{{.File}}">{{.Name}}</a> <span class="count">({{.Activities}})</span></li>
+  {{- end}}
+</ul>
+{{- end}}
+<p class="generated">Generado el {{.Generated}}</p>
+</body>
+</html>
+`))
@@ -0,0 +1,265 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// ICSSummary cuenta los calendarios escritos por ExportICS
+type ICSSummary struct {
+	Teachers  int
+	Rooms     int
+	Sections  int
+	Semesters int
+}
+
+// Total retorna la cantidad de archivos .ics escritos
+func (s ICSSummary) Total() int {
+	return s.Teachers + s.Rooms + s.Sections + s.Semesters
+}
+
+// ExportICS escribe en dir un calendario iCalendar (.ics) por profesor, sala, sección de curso y carrera-semestre
+// (según PlanLocation), en los subdirectorios profesores, salas, secciones y carreras. Cada actividad programada es un
+// evento semanal que se repite entre el inicio y el término del semestre, sin las fechas de los feriados.
+func ExportICS(activities []domain.Activity, planLocations map[string]map[string]int, cal domain.SemesterCalendar, dir string) (ICSSummary, error) {
+	var summary ICSSummary
+	stamp := time.Now().UTC()
+
+	teachers := make(map[string][]*domain.Activity)
+	rooms := make(map[string][]*domain.Activity)
+	sections := make(map[string][]*domain.Activity)
+	semesters := make(map[string][]*domain.Activity)
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 {
+			continue
+		}
+		for _, t := range a.TeacherNames {
+			teachers[t] = append(teachers[t], a)
+		}
+		if a.Room != "" {
+			rooms[a.Room] = append(rooms[a.Room], a)
+		}
+		for _, s := range a.Sections {
+			key := fmt.Sprintf("%s sección %d", a.CourseCode, s)
+			sections[key] = append(sections[key], a)
+		}
+		for major, semester := range planLocations[a.CourseCode] {
+			key := fmt.Sprintf("%s semestre %d", major, semester)
+			semesters[key] = append(semesters[key], a)
+		}
+	}
+
+	groups := []struct {
+		subdir string
+		prefix string
+		byName map[string][]*domain.Activity
+		count  *int
+	}{
+		{"profesores", "Profesor", teachers, &summary.Teachers},
+		{"salas", "Sala", rooms, &summary.Rooms},
+		{"secciones", "", sections, &summary.Sections},
+		{"carreras", "", semesters, &summary.Semesters},
+	}
+	for _, g := range groups {
+		path := filepath.Join(dir, g.subdir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return summary, err
+		}
+
+		names := make([]string, 0, len(g.byName))
+		for name := range g.byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		files := uniqueFileNames(names)
+
+		for _, name := range names {
+			calName := name
+			if g.prefix != "" {
+				calName = g.prefix + " " + name
+			}
+			if err := writeICSFile(filepath.Join(path, files[name]+".ics"), calName, g.byName[name], cal, stamp); err != nil {
+				return summary, err
+			}
+			*g.count++
+		}
+	}
+	return summary, nil
+}
+
+func writeICSFile(path, name string, activities []*domain.Activity, cal domain.SemesterCalendar, stamp time.Time) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteICS(f, name, activities, cal, stamp); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteICS escribe un calendario con un evento semanal recurrente por actividad programada. Las horas se escriben
+// en hora local "flotante" (sin zona), así una clase de las 08:30 sigue a las 08:30 después de un cambio de horario;
+// la zona del semestre se indica con X-WR-TIMEZONE para los clientes que la usan.
+func WriteICS(w io.Writer, name string, activities []*domain.Activity, cal domain.SemesterCalendar, stamp time.Time) error {
+	iw := &icsWriter{w: bufio.NewWriter(w)}
+
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//UDP FIC//timetabling//ES")
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	iw.line("X-WR-CALNAME:" + escapeICS(name))
+	if cal.TimeZone != "" {
+		iw.line("X-WR-TIMEZONE:" + cal.TimeZone)
+	}
+
+	sorted := make([]*domain.Activity, len(activities))
+	copy(sorted, activities)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Block != sorted[j].Block {
+			return sorted[i].Block < sorted[j].Block
+		}
+		return sorted[i].Code < sorted[j].Code
+	})
+	for _, a := range sorted {
+		if err := writeEvent(iw, a, cal, stamp); err != nil {
+			return err
+		}
+	}
+
+	iw.line("END:VCALENDAR")
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
+
+// writeEvent escribe el VEVENT de una actividad; se omite si el semestre no tiene ese día de la semana
+func writeEvent(iw *icsWriter, a *domain.Activity, cal domain.SemesterCalendar, stamp time.Time) error {
+	day := a.Block / domain.BlocksPerDay
+	slot := a.Block % domain.BlocksPerDay
+	last := slot + a.Span() - 1
+	if last >= len(domain.Grid.Slots) {
+		return fmt.Errorf("actividad %s: termina fuera de la grilla", a.Code)
+	}
+	weekday, err := domain.Weekday(day)
+	if err != nil {
+		return err
+	}
+
+	// Primera fecha del semestre que cae en el día de la actividad
+	first := cal.Start
+	for first.Weekday() != weekday {
+		first = first.AddDate(0, 0, 1)
+	}
+	if first.After(cal.End) {
+		return nil
+	}
+
+	start := domain.Grid.Slots[slot].Start
+	end := domain.Grid.Slots[last].End
+
+	var exdates []string
+	for d := first; !d.After(cal.End); d = d.AddDate(0, 0, 7) {
+		if _, ok := cal.HolidayOn(d); ok {
+			exdates = append(exdates, icsDateTime(d, start))
+		}
+	}
+
+	iw.line("BEGIN:VEVENT")
+	iw.line("UID:" + a.Code + "@timetabling-udp")
+	iw.line("DTSTAMP:" + stamp.Format("20060102T150405Z"))
+	iw.line("DTSTART:" + icsDateTime(first, start))
+	iw.line("DTEND:" + icsDateTime(first, end))
+	iw.line("RRULE:FREQ=WEEKLY;UNTIL=" + cal.End.Format("20060102") + "T235959")
+	if len(exdates) > 0 {
+		iw.line("EXDATE:" + strings.Join(exdates, ","))
+	}
+	iw.line("SUMMARY:" + escapeICS(fmt.Sprintf("%s %s - %s", a.CourseCode, a.CourseName, a.Type)))
+	if a.Room != "" {
+		iw.line("LOCATION:" + escapeICS(a.Room))
+	}
+	description := fmt.Sprintf("Actividad: %s\nSecciones: %s\nProfesores: %s\nEstudiantes: %d",
+		a.Code, joinInts(a.Sections), strings.Join(a.TeacherNames, ", "), a.Students)
+	iw.line("DESCRIPTION:" + escapeICS(description))
+	iw.line("CATEGORIES:" + escapeICS(string(a.Type)))
+	iw.line("END:VEVENT")
+	return nil
+}
+
+// icsDateTime arma una fecha-hora local flotante (AAAAMMDDTHHMMSS, los segundos en 00) a partir de la fecha y "HH:MM"
+func icsDateTime(date time.Time, hhmm string) string {
+	return date.Format("20060102") + "T" + strings.Replace(hhmm, ":", "", 1) + "00"
+}
+
+// escapeICS escapa un texto según RFC 5545: barra invertida, punto y coma, coma y saltos de línea
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsWriter escribe líneas terminadas en CRLF, plegadas a 75 bytes sin cortar caracteres UTF-8
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (iw *icsWriter) line(s string) {
+	if iw.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut-- // no cortar en medio de un carácter
+		}
+		_, iw.err = iw.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // las líneas de continuación comienzan con un espacio
+	}
+	_, err := iw.w.WriteString(s + "\r\n")
+	if iw.err == nil {
+		iw.err = err
+	}
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// slug convierte un nombre en un nombre de archivo: minúsculas, sin tildes, y guiones en lugar de otros caracteres
+func slug(name string) string {
+	name = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+		"Á", "a", "É", "e", "Í", "i", "Ó", "o", "Ú", "u", "Ü", "u", "Ñ", "n").Replace(name)
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if s := strings.TrimSuffix(b.String(), "-"); s != "" {
+		return s
+	}
+	return "sin-nombre"
+}
@@ -3,6 +3,7 @@ package exporter
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
@@ -12,11 +13,37 @@ import (
 // ScheduleExport es la estructura del JSON exportado.
 type ScheduleExport struct {
 	GeneratedAt string           `json:"generated_at"`
+	Grid        GridExport       `json:"grid"`
 	Summary     ScheduleSummary  `json:"summary"`
 	Schedule    []DaySchedule    `json:"schedule"`
 	Activities  []ActivityExport `json:"activities"`
 }
 
+// GridExport describe la grilla semanal usada, para que los visualizadores no la dupliquen.
+type GridExport struct {
+	Days            []string         `json:"days"`
+	DaySlots        []int            `json:"day_slots"` // Slots utilizables por día
+	Slots           []TimeSlotExport `json:"slots"`
+	BlocksPerDay    int              `json:"blocks_per_day"`
+	ProtectedBlocks []int            `json:"protected_blocks"`
+	BlockedPeriods  []BlockedExport  `json:"blocked_periods"`
+}
+
+// BlockedExport representa un periodo bloqueado, majors vacío aplica a toda la institución.
+type BlockedExport struct {
+	Name   string   `json:"name"`
+	Day    string   `json:"day"`
+	Blocks []int    `json:"blocks"`
+	Majors []string `json:"majors"`
+}
+
+// TimeSlotExport representa un slot horario.
+type TimeSlotExport struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Label string `json:"label"`
+}
+
 // ScheduleSummary contiene estadísticas del horario.
 type ScheduleSummary struct {
 	TotalActivities  int     `json:"total_activities"`
@@ -24,6 +51,18 @@ type ScheduleSummary struct {
 	TotalRooms       int     `json:"total_rooms"`
 	AYOnWednesday    float64 `json:"ay_on_wednesday_percent"`
 	MirrorCompliance float64 `json:"mirror_compliance_percent"`
+
+	TeacherSatisfaction        float64                     `json:"teacher_satisfaction_percent"`
+	TeacherSatisfactionDetails []TeacherSatisfactionExport `json:"teacher_satisfaction"`
+}
+
+// TeacherSatisfactionExport representa la satisfacción de preferencias de un profesor.
+type TeacherSatisfactionExport struct {
+	Teacher         string  `json:"teacher"`
+	TotalBlocks     int     `json:"total_blocks"`
+	PreferredBlocks int     `json:"preferred_blocks"`
+	UndesiredBlocks int     `json:"undesired_blocks"`
+	Satisfaction    float64 `json:"satisfaction_percent"`
 }
 
 // DaySchedule representa un día de la semana.
@@ -56,29 +95,14 @@ type ActivityExport struct {
 	Sections   []int    `json:"sections"`
 }
 
-var dayNames = []string{"Lunes", "Martes", "Miércoles", "Jueves", "Viernes"}
-
-// Time slots - Horarios UDP
-var timeSlots = []string{
-	"08:30-09:50",
-	"10:00-11:20",
-	"11:30-12:50",
-	"13:00-14:20",
-	"14:30-15:50",
-	"16:00-17:20",
-	"17:25-18:45",
-}
-
-// Start times for building ranges
-var startTimes = []string{"08:30", "10:00", "11:30", "13:00", "14:30", "16:00", "17:25"}
-var endTimes = []string{"09:50", "11:20", "12:50", "14:20", "15:50", "17:20", "18:45"}
-
 // ExportScheduleToJSON exporta el horario completo a un archivo JSON.
-func ExportScheduleToJSON(activities []domain.Activity, filename string) error {
+// satisfaction es la satisfacción de preferencias por profesor (puede ser nil).
+func ExportScheduleToJSON(activities []domain.Activity, satisfaction []domain.TeacherSatisfaction, filename string) error {
 	// Crear export
 	export := ScheduleExport{
 		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
-		Summary:     calculateSummary(activities),
+		Grid:        buildGridExport(),
+		Summary:     calculateSummary(activities, satisfaction),
 		Schedule:    buildDaySchedule(activities),
 		Activities:  buildActivityList(activities),
 	}
@@ -89,10 +113,15 @@ func ExportScheduleToJSON(activities []domain.Activity, filename string) error {
 		return err
 	}
 
+	// Crear directorio de salida si no existe
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
 	return os.WriteFile(filename, data, 0644)
 }
 
-func calculateSummary(activities []domain.Activity) ScheduleSummary {
+func calculateSummary(activities []domain.Activity, satisfaction []domain.TeacherSatisfaction) ScheduleSummary {
 	courses := make(map[string]bool)
 	rooms := make(map[string]bool)
 	ayOnWed := 0
@@ -106,7 +135,7 @@ func calculateSummary(activities []domain.Activity) ScheduleSummary {
 		if a.Type == domain.AY {
 			totalAY++
 			day := a.Block / domain.BlocksPerDay
-			if day == 2 { // Miércoles
+			if day == domain.WednesdayDay {
 				ayOnWed++
 			}
 		}
@@ -117,21 +146,73 @@ func calculateSummary(activities []domain.Activity) ScheduleSummary {
 		ayPercent = float64(ayOnWed) / float64(totalAY) * 100
 	}
 
+	details := make([]TeacherSatisfactionExport, 0, len(satisfaction))
+	for _, s := range satisfaction {
+		details = append(details, TeacherSatisfactionExport{
+			Teacher:         s.Teacher,
+			TotalBlocks:     s.TotalBlocks,
+			PreferredBlocks: s.PreferredBlocks,
+			UndesiredBlocks: s.UndesiredBlocks,
+			Satisfaction:    s.Satisfaction,
+		})
+	}
+
 	return ScheduleSummary{
 		TotalActivities:  len(activities),
 		TotalCourses:     len(courses),
 		TotalRooms:       len(rooms),
 		AYOnWednesday:    ayPercent,
 		MirrorCompliance: 0,
+
+		TeacherSatisfaction:        domain.AverageSatisfaction(satisfaction),
+		TeacherSatisfactionDetails: details,
+	}
+}
+
+func buildGridExport() GridExport {
+	grid := GridExport{
+		Days:            domain.DayNames,
+		DaySlots:        make([]int, domain.DaysPerWeek),
+		Slots:           make([]TimeSlotExport, domain.BlocksPerDay),
+		BlocksPerDay:    domain.BlocksPerDay,
+		ProtectedBlocks: []int{},
+		BlockedPeriods:  []BlockedExport{},
+	}
+	for d := 0; d < domain.DaysPerWeek; d++ {
+		grid.DaySlots[d] = domain.SlotsInDay(d)
+	}
+	for i, ts := range domain.Grid.Slots {
+		grid.Slots[i] = TimeSlotExport{Start: ts.Start, End: ts.End, Label: ts.Label()}
+	}
+	for b := 0; b < domain.TotalBlocks; b++ {
+		if domain.IsProtectedBlock(b) {
+			grid.ProtectedBlocks = append(grid.ProtectedBlocks, b)
+		}
+	}
+	for _, p := range domain.Grid.Blocked {
+		be := BlockedExport{
+			Name:   p.Name,
+			Day:    domain.DayNames[p.Day],
+			Blocks: []int{},
+			Majors: p.Majors,
+		}
+		if be.Majors == nil {
+			be.Majors = []string{}
+		}
+		for slot := p.From; slot <= p.To; slot++ {
+			be.Blocks = append(be.Blocks, p.Day*domain.BlocksPerDay+slot)
+		}
+		grid.BlockedPeriods = append(grid.BlockedPeriods, be)
 	}
+	return grid
 }
 
 func buildDaySchedule(activities []domain.Activity) []DaySchedule {
-	schedule := make([]DaySchedule, 5)
+	schedule := make([]DaySchedule, domain.DaysPerWeek)
 
-	for d := 0; d < 5; d++ {
+	for d := 0; d < domain.DaysPerWeek; d++ {
 		schedule[d] = DaySchedule{
-			Day:    dayNames[d],
+			Day:    domain.DayNames[d],
 			Blocks: make([]BlockSlot, domain.BlocksPerDay),
 		}
 
@@ -139,7 +220,7 @@ func buildDaySchedule(activities []domain.Activity) []DaySchedule {
 			block := d*domain.BlocksPerDay + s
 			schedule[d].Blocks[s] = BlockSlot{
 				Block:      block,
-				Time:       timeSlots[s],
+				Time:       domain.Grid.Slots[s].Label(),
 				Activities: []ActivityExport{},
 			}
 		}
@@ -181,31 +262,16 @@ func buildActivityList(activities []domain.Activity) []ActivityExport {
 }
 
 func activityToExport(a domain.Activity) ActivityExport {
-	day := 0
-	slot := 0
 	dayName := ""
 	timeSlot := ""
-	duration := a.Duration
-	if duration < 1 {
-		duration = 1
-	}
+	duration := a.Span()
 	endBlock := a.Block + duration - 1
 
 	if a.Block >= 0 && a.Block < domain.TotalBlocks {
-		day = a.Block / domain.BlocksPerDay
-		slot = a.Block % domain.BlocksPerDay
-		dayName = dayNames[day]
+		dayName = domain.DayNames[a.Block/domain.BlocksPerDay]
 
 		// Calcular rango horario
-		endSlot := slot + duration - 1
-		if endSlot >= domain.BlocksPerDay {
-			endSlot = domain.BlocksPerDay - 1
-		}
-		if duration == 1 {
-			timeSlot = timeSlots[slot]
-		} else {
-			timeSlot = startTimes[slot] + "-" + endTimes[endSlot]
-		}
+		timeSlot = domain.BlockTimeRange(a.Block, duration)
 	}
 
 	typeStr := "CATEDRA"
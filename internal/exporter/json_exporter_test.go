@@ -0,0 +1,79 @@
+package exporter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// TestScheduleJSONRoundTrip verifica que exportar un horario e importarlo de vuelta conserve bloque, sala,
+// profesores, duración, estudiantes y los datos que identifican a cada actividad
+func TestScheduleJSONRoundTrip(t *testing.T) {
+	cat := func(course string, sections []int) string { return loader.SiblingGroupID(course, sections) }
+	activities := []domain.Activity{
+		domain.NewActivity(1, "CIT2000-CAT-1-S1", "CIT2000", "Estructuras de Datos", domain.CAT, 1, []int{1, 2}, 70, []string{"Ana Pérez"}, cat("CIT2000", []int{1, 2}), 1),
+		domain.NewActivity(2, "CIT2000-CAT-1-S2", "CIT2000", "Estructuras de Datos", domain.CAT, 1, []int{1, 2}, 70, []string{"Ana Pérez"}, cat("CIT2000", []int{1, 2}), 1),
+		domain.NewActivity(3, "CIT2000-CAT-2-S1", "CIT2000", "Estructuras de Datos", domain.CAT, 2, []int{3}, 35, []string{"Bruno Soto", "Carla Díaz"}, cat("CIT2000", []int{3}), 1),
+		domain.NewActivity(4, "CIT2000-AY-1-S1", "CIT2000", "Estructuras de Datos", domain.AY, 1, []int{1, 2, 3}, 105, []string{"Diego Ruiz"}, "", 1),
+		domain.NewActivity(5, "CIT2000-LAB-1-S1", "CIT2000", "Estructuras de Datos", domain.LAB, 1, []int{1}, 30, []string{"Carla Díaz"}, "", 2),
+		domain.NewActivity(6, "CBM1000-CAT-1-S1", "CBM1000", "Cálculo I", domain.CAT, 1, []int{1}, 60, []string{"Elena Mora"}, cat("CBM1000", []int{1}), 1),
+	}
+	placements := []struct {
+		block int
+		room  string
+	}{
+		{0, "101"}, {14, "101"}, {8, "LAB A"}, {16, "205"}, {4, "LAB A"}, {-1, ""}, // la última queda sin programar
+	}
+	for i, p := range placements {
+		activities[i].Block = p.block
+		activities[i].Room = p.room
+	}
+
+	filename := filepath.Join(t.TempDir(), "schedule.json")
+	if err := ExportScheduleToJSON(activities, nil, filename); err != nil {
+		t.Fatalf("exportar: %v", err)
+	}
+	imported, err := loader.ImportScheduleFromJSON(filename)
+	if err != nil {
+		t.Fatalf("importar: %v", err)
+	}
+	if len(imported) != len(activities) {
+		t.Fatalf("se importaron %d actividades, se exportaron %d", len(imported), len(activities))
+	}
+
+	byCode := make(map[string]domain.Activity, len(imported))
+	for _, a := range imported {
+		byCode[a.Code] = a
+	}
+	for _, want := range activities {
+		got, ok := byCode[want.Code]
+		if !ok {
+			t.Errorf("%s: no está en el horario importado", want.Code)
+			continue
+		}
+		fields := []struct {
+			name      string
+			got, want interface{}
+		}{
+			{"block", got.Block, want.Block},
+			{"room", got.Room, want.Room},
+			{"teachers", got.TeacherNames, want.TeacherNames},
+			{"duration", got.Duration, want.Duration},
+			{"students", got.Students, want.Students},
+			{"sections", got.Sections, want.Sections},
+			{"type", got.Type, want.Type},
+			{"course", got.CourseCode, want.CourseCode},
+			{"course_name", got.CourseName, want.CourseName},
+			{"event_number", got.EventNumber, want.EventNumber},
+			{"sibling_group", got.SiblingGroupID, want.SiblingGroupID},
+		}
+		for _, f := range fields {
+			if !reflect.DeepEqual(f.got, f.want) {
+				t.Errorf("%s: %s importado %v, exportado %v", want.Code, f.name, f.got, f.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// Campos disponibles para las columnas de las exportaciones tabulares (CSV y XLSX)
+const (
+	FieldCode       = "code"        // Código de la sesión ("CBE2000-CAT-1-S1")
+	FieldCourseCode = "course_code" // Sigla del curso
+	FieldCourseName = "course_name"
+	FieldSections   = "sections" // Secciones separadas por ListSeparator
+	FieldType       = "type"     // CATEDRA, AYUDANTIA o LABORATORIO
+	FieldDay        = "day"      // Nombre del día según la grilla
+	FieldDayNumber  = "day_number"
+	FieldStart      = "start" // Hora de inicio ("08:30")
+	FieldEnd        = "end"   // Hora de término del último bloque que ocupa
+	FieldBlock      = "block"
+	FieldDuration   = "duration"
+	FieldRoom       = "room"
+	FieldTeachers   = "teachers" // Profesores separados por ListSeparator
+	FieldStudents   = "students"
+)
+
+// TableFields lista todos los campos que acepta un mapeo de columnas
+var TableFields = []string{
+	FieldCode, FieldCourseCode, FieldCourseName, FieldSections, FieldType, FieldDay, FieldDayNumber,
+	FieldStart, FieldEnd, FieldBlock, FieldDuration, FieldRoom, FieldTeachers, FieldStudents,
+}
+
+// defaultFields son las columnas por defecto
+var defaultFields = []string{
+	FieldCourseCode, FieldCourseName, FieldSections, FieldType, FieldDay, FieldStart, FieldEnd,
+	FieldRoom, FieldTeachers, FieldStudents,
+}
+
+// numericFields se escriben como números en XLSX
+var numericFields = map[string]bool{
+	FieldDayNumber: true, FieldBlock: true, FieldDuration: true, FieldStudents: true,
+}
+
+// Column es una columna de la planilla: un campo de la actividad o un valor fijo, con su encabezado
+type Column struct {
+	Header string `json:"header"`
+	Field  string `json:"field,omitempty"`
+	Value  string `json:"value,omitempty"` // Valor fijo cuando no hay campo (p. ej. el periodo académico)
+}
+
+// ColumnMapping define las columnas de las exportaciones tabulares, para calzar con la plantilla de Registro Curricular
+type ColumnMapping struct {
+	Columns       []Column `json:"columns"`
+	ListSeparator string   `json:"list_separator"` // Separador de secciones y profesores, por defecto ", "
+	Sheet         string   `json:"sheet"`          // Nombre de la hoja XLSX, por defecto "Horario"
+	CSVDelimiter  string   `json:"csv_delimiter"`  // Separador del CSV, por defecto ","; Excel en español usa ";"
+}
+
+// DefaultColumnMapping retorna las columnas por defecto, con el nombre del campo como encabezado
+func DefaultColumnMapping() ColumnMapping {
+	m := ColumnMapping{ListSeparator: ", ", Sheet: "Horario"}
+	for _, f := range defaultFields {
+		m.Columns = append(m.Columns, Column{Header: f, Field: f})
+	}
+	return m
+}
+
+// LoadColumnMapping lee un mapeo de columnas en JSON y valida sus campos
+func LoadColumnMapping(path string) (ColumnMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ColumnMapping{}, err
+	}
+	var m ColumnMapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ColumnMapping{}, err
+	}
+	if len(m.Columns) == 0 {
+		return ColumnMapping{}, fmt.Errorf("el mapeo no define columnas")
+	}
+	known := make(map[string]bool)
+	for _, f := range TableFields {
+		known[f] = true
+	}
+	for i, c := range m.Columns {
+		if c.Field != "" && !known[c.Field] {
+			return ColumnMapping{}, fmt.Errorf("columna %d (%q): campo desconocido %q", i+1, c.Header, c.Field)
+		}
+		if c.Field != "" && c.Value != "" {
+			return ColumnMapping{}, fmt.Errorf("columna %d (%q): tiene campo y valor fijo", i+1, c.Header)
+		}
+	}
+	if m.ListSeparator == "" {
+		m.ListSeparator = ", "
+	}
+	if m.Sheet == "" {
+		m.Sheet = "Horario"
+	}
+	if utf8.RuneCountInString(m.Sheet) > 31 || strings.ContainsAny(m.Sheet, `[]:*?/\`) {
+		return ColumnMapping{}, fmt.Errorf("nombre de hoja inválido %q (máximo 31 caracteres, sin []:*?/\\)", m.Sheet)
+	}
+	if utf8.RuneCountInString(m.CSVDelimiter) > 1 || strings.ContainsAny(m.CSVDelimiter, "\"\r\n") {
+		return ColumnMapping{}, fmt.Errorf("separador de CSV inválido %q", m.CSVDelimiter)
+	}
+	return m, nil
+}
+
+// Headers retorna los encabezados de las columnas
+func (m ColumnMapping) Headers() []string {
+	headers := make([]string, len(m.Columns))
+	for i, c := range m.Columns {
+		headers[i] = c.Header
+	}
+	return headers
+}
+
+// Row retorna los valores de las columnas para una actividad programada
+func (m ColumnMapping) Row(a *domain.Activity) []string {
+	row := make([]string, len(m.Columns))
+	for i, c := range m.Columns {
+		if c.Field == "" {
+			row[i] = c.Value
+			continue
+		}
+		row[i] = m.fieldValue(a, c.Field)
+	}
+	return row
+}
+
+func (m ColumnMapping) fieldValue(a *domain.Activity, field string) string {
+	duration := a.Span()
+	day := a.Block / domain.BlocksPerDay
+	slot := a.Block % domain.BlocksPerDay
+	endSlot := slot + duration - 1
+	if endSlot >= domain.BlocksPerDay {
+		endSlot = domain.BlocksPerDay - 1
+	}
+
+	switch field {
+	case FieldCode:
+		return a.Code
+	case FieldCourseCode:
+		return a.CourseCode
+	case FieldCourseName:
+		return a.CourseName
+	case FieldSections:
+		parts := make([]string, len(a.Sections))
+		for i, s := range a.Sections {
+			parts[i] = strconv.Itoa(s)
+		}
+		return strings.Join(parts, m.ListSeparator)
+	case FieldType:
+		return string(a.Type)
+	case FieldDay:
+		return domain.DayNames[day]
+	case FieldDayNumber:
+		return strconv.Itoa(day + 1)
+	case FieldStart:
+		return domain.Grid.Slots[slot].Start
+	case FieldEnd:
+		return domain.Grid.Slots[endSlot].End
+	case FieldBlock:
+		return strconv.Itoa(a.Block)
+	case FieldDuration:
+		return strconv.Itoa(duration)
+	case FieldRoom:
+		return a.Room
+	case FieldTeachers:
+		return strings.Join(a.TeacherNames, m.ListSeparator)
+	case FieldStudents:
+		return strconv.Itoa(a.Students)
+	}
+	return ""
+}
+
+// scheduledRows retorna las actividades programadas ordenadas por curso, bloque y código (una fila por sesión)
+// y la cantidad de actividades sin programar que se omiten
+func scheduledRows(activities []domain.Activity) ([]*domain.Activity, int) {
+	rows := make([]*domain.Activity, 0, len(activities))
+	skipped := 0
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 || a.Block >= domain.TotalBlocks {
+			skipped++
+			continue
+		}
+		rows = append(rows, a)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CourseCode != rows[j].CourseCode {
+			return rows[i].CourseCode < rows[j].CourseCode
+		}
+		if rows[i].Block != rows[j].Block {
+			return rows[i].Block < rows[j].Block
+		}
+		return rows[i].Code < rows[j].Code
+	})
+	return rows, skipped
+}
+
+// ExportScheduleToCSV escribe una fila por sesión programada con las columnas del mapeo y retorna las filas
+// escritas y las actividades sin programar omitidas. El archivo se escribe en UTF-8 con BOM para que Excel
+// reconozca las tildes.
+func ExportScheduleToCSV(activities []domain.Activity, mapping ColumnMapping, filename string) (int, int, error) {
+	// Crear directorio de salida si no existe
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return 0, 0, err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rows, skipped := scheduledRows(activities)
+	if _, err := f.WriteString("\ufeff"); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	w := csv.NewWriter(f)
+	if mapping.CSVDelimiter != "" {
+		w.Comma, _ = utf8.DecodeRuneInString(mapping.CSVDelimiter)
+	}
+	w.Write(mapping.Headers())
+	for _, a := range rows {
+		w.Write(mapping.Row(a))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	return len(rows), skipped, f.Close()
+}
@@ -0,0 +1,166 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// Partes fijas de un libro XLSX (Office Open XML) con una hoja; los textos van como inlineStr, así no se necesita
+// la tabla de textos compartidos. El estilo 1 es el encabezado en negrita.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`
+)
+
+// ExportScheduleToXLSX escribe un libro XLSX con una hoja y una fila por sesión programada, con las columnas del
+// mapeo; los campos numéricos (estudiantes, bloque, duración, número de día) se escriben como números. Retorna las
+// filas escritas y las actividades sin programar omitidas.
+func ExportScheduleToXLSX(activities []domain.Activity, mapping ColumnMapping, filename string) (int, int, error) {
+	rows, skipped := scheduledRows(activities)
+
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	// Fijar la fila de encabezados al desplazarse
+	sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	sheet.WriteString(`<sheetData>`)
+
+	header := mapping.Headers()
+	sheet.WriteString(`<row r="1">`)
+	for c, h := range header {
+		writeXLSXString(&sheet, cellRef(c, 1), h, 1)
+	}
+	sheet.WriteString(`</row>`)
+
+	for i, a := range rows {
+		r := i + 2
+		sheet.WriteString(`<row r="` + strconv.Itoa(r) + `">`)
+		for c, v := range mapping.Row(a) {
+			if v == "" {
+				continue
+			}
+			ref := cellRef(c, r)
+			if numericFields[mapping.Columns[c].Field] {
+				sheet.WriteString(`<c r="` + ref + `"><v>` + v + `</v></c>`)
+			} else {
+				writeXLSXString(&sheet, ref, v, 0)
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData>`)
+	if len(header) > 0 {
+		sheet.WriteString(`<autoFilter ref="A1:` + cellRef(len(header)-1, len(rows)+1) + `"/>`)
+	}
+	sheet.WriteString(`</worksheet>`)
+
+	var workbook bytes.Buffer
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	xml.EscapeText(&workbook, []byte(mapping.Sheet))
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets>`)
+	if len(header) > 0 {
+		// El autofiltro de la hoja necesita este nombre definido para que Excel lo reconozca
+		workbook.WriteString(`<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">'`)
+		xml.EscapeText(&workbook, []byte(strings.ReplaceAll(mapping.Sheet, "'", "''")))
+		workbook.WriteString(`'!$A$1:$` + columnName(len(header)-1) + `$` + strconv.Itoa(len(rows)+1) + `</definedName></definedNames>`)
+	}
+	workbook.WriteString(`</workbook>`)
+
+	// Crear directorio de salida si no existe
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return 0, 0, err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	zw := zip.NewWriter(f)
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", workbook.Bytes()},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/styles.xml", []byte(xlsxStyles)},
+		{"xl/worksheets/sheet1.xml", sheet.Bytes()},
+	}
+	for _, p := range parts {
+		w, err := zw.Create(p.name)
+		if err == nil {
+			_, err = w.Write(p.data)
+		}
+		if err != nil {
+			f.Close()
+			return 0, 0, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	return len(rows), skipped, f.Close()
+}
+
+// writeXLSXString escribe una celda de texto en línea con el estilo indicado (0 = normal, 1 = negrita)
+func writeXLSXString(buf *bytes.Buffer, ref, value string, style int) {
+	buf.WriteString(`<c r="` + ref + `" t="inlineStr"`)
+	if style != 0 {
+		buf.WriteString(` s="` + strconv.Itoa(style) + `"`)
+	}
+	buf.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(buf, []byte(value))
+	buf.WriteString(`</t></is></c>`)
+}
+
+// cellRef retorna la referencia de una celda ("A1", "AB12") a partir de la columna (desde 0) y la fila (desde 1)
+func cellRef(col, row int) string {
+	return columnName(col) + strconv.Itoa(row)
+}
+
+// columnName retorna el nombre de una columna de planilla a partir de su índice desde 0 (0 = A, 26 = AA)
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"sort"
+
 	"timetabling-UDP/internal/domain"
 	"timetabling-UDP/internal/utils"
 )
@@ -62,6 +64,16 @@ func (g *ConflictGraph) Neighbors(id int) []int {
 	return neighbors
 }
 
+// VertexIDs retorna los IDs de los vértices ordenados, para recorrer el grafo de forma reproducible.
+func (g *ConflictGraph) VertexIDs() []int {
+	ids := make([]int, 0, len(g.Vertices))
+	for id := range g.Vertices {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 // NumVertices retorna el número de vértices.
 func (g *ConflictGraph) NumVertices() int {
 	return len(g.Vertices)
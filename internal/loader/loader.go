@@ -1,12 +1,14 @@
 package loader
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"timetabling-UDP/internal/domain"
 )
@@ -212,7 +214,7 @@ func LoadActivitiesWithExpansion(ofertaPath, coursesPath string) ([]domain.Activ
 			// SiblingGroupID para agrupar actividades espejo (catedras)
 			siblingGroup := ""
 			if eventType == domain.CAT {
-				siblingGroup = buildSiblingGroupID(c.CourseCode, a.LinkedSections)
+				siblingGroup = SiblingGroupID(c.CourseCode, a.LinkedSections)
 			}
 
 			// Crear N actividades (sesiones) para este evento
@@ -240,8 +242,8 @@ func LoadActivitiesWithExpansion(ofertaPath, coursesPath string) ([]domain.Activ
 	return activities, nil
 }
 
-// buildSiblingGroupID genera un ID único para agrupar cátedras hermanas.
-func buildSiblingGroupID(courseCode string, sections []int) string {
+// SiblingGroupID genera un ID único para agrupar cátedras hermanas, el mismo al cargar la oferta y al importar un horario.
+func SiblingGroupID(courseCode string, sections []int) string {
 	if len(sections) == 0 {
 		return ""
 	}
@@ -287,16 +289,15 @@ func LoadRooms(path string) ([]domain.Room, error) {
 		if len(record) < 2 {
 			continue
 		}
-		capacity, _ := strconv.Atoi(record[1])
-		roomType := domain.RoomClassroom
-		if strings.HasPrefix(record[0], "LAB") {
-			roomType = domain.RoomLab
+		capacity, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("línea %d: capacidad %q de la sala %s no es un número", i+1, record[1], record[0])
 		}
 		rooms = append(rooms, domain.Room{
 			ID:       i,
 			Code:     record[0],
 			Capacity: capacity,
-			Type:     roomType,
+			Type:     roomTypeFromCode(record[0]),
 		})
 	}
 	return rooms, nil
@@ -304,10 +305,34 @@ func LoadRooms(path string) ([]domain.Room, error) {
 
 // TeacherJSON representa un profesor de profesores.json
 type TeacherJSON struct {
-	ID                int                `json:"id"`
-	Name              string             `json:"name"`
-	UnavailableBlocks map[string][]int   `json:"unavailable_blocks"`
-	TeachingLoad      []TeachingLoadJSON `json:"teaching_load"`
+	ID           int                `json:"id"`
+	Name         string             `json:"name"`
+	Availability AvailabilityJSON   `json:"availability"`
+	TeachingLoad []TeachingLoadJSON `json:"teaching_load"`
+
+	// UnavailableBlocks es el formato antiguo (ambiguo), se rechaza si viene con datos
+	UnavailableBlocks map[string][]int `json:"unavailable_blocks"`
+}
+
+// AvailabilityJSON representa la disponibilidad de un profesor. Las llaves son nombres de días
+// ("lunes", "miércoles", ...) o "todos", y los rangos son slots dentro del día (0 = 08:30) inclusive.
+type AvailabilityJSON struct {
+	Unavailable   map[string][]SlotRangeJSON      `json:"unavailable"`
+	AvailableOnly map[string][]SlotRangeJSON      `json:"available_only"`
+	Preferences   map[string][]SlotPreferenceJSON `json:"preferences"`
+}
+
+// SlotRangeJSON representa un rango de slots de un día
+type SlotRangeJSON struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// SlotPreferenceJSON representa un rango de slots con nivel de preferencia (-2 a 2)
+type SlotPreferenceJSON struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Level int `json:"level"`
 }
 
 // TeachingLoadJSON representa la carga docente
@@ -326,27 +351,136 @@ func LoadTeachers(path string) ([]domain.Teacher, error) {
 		return nil, err
 	}
 
+	// Una llave mal escrita en availability (p. ej. "unavalable") descartaría la restricción en silencio
 	var teachersJSON []TeacherJSON
-	if err := json.Unmarshal(data, &teachersJSON); err != nil {
+	if err := decodeStrict(data, &teachersJSON); err != nil {
 		return nil, err
 	}
 
 	var teachers []domain.Teacher
 	for _, t := range teachersJSON {
-		// Aplanar bloques no disponibles de todos los días
-		var busyBlocks []int
-		for _, blocks := range t.UnavailableBlocks {
-			busyBlocks = append(busyBlocks, blocks...)
+		if len(t.UnavailableBlocks) > 0 {
+			return nil, fmt.Errorf("profesor %d (%s): el campo unavailable_blocks ya no se soporta, usar availability", t.ID, t.Name)
+		}
+
+		availability, err := parseAvailability(t.Availability)
+		if err != nil {
+			return nil, fmt.Errorf("profesor %d (%s): %w", t.ID, t.Name, err)
 		}
+
 		teachers = append(teachers, domain.Teacher{
-			ID:         t.ID,
-			Name:       t.Name,
-			BusyBlocks: busyBlocks,
+			ID:           t.ID,
+			Name:         t.Name,
+			Availability: availability,
 		})
 	}
 	return teachers, nil
 }
 
+// decodeStrict decodifica JSON rechazando campos desconocidos, para los archivos donde un nombre mal escrito
+// cambiaría el resultado sin aviso
+func decodeStrict(data []byte, dst interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
+
+// parseAvailability convierte la disponibilidad del JSON al modelo de dominio, validando días y slots
+func parseAvailability(a AvailabilityJSON) (domain.Availability, error) {
+	av := domain.Availability{
+		Unavailable:   make(map[int][]domain.SlotRange),
+		AvailableOnly: make(map[int][]domain.SlotRange),
+		Preferences:   make(map[int][]domain.SlotPreference),
+	}
+
+	for key, ranges := range a.Unavailable {
+		days, err := parseDayKey(key)
+		if err != nil {
+			return av, fmt.Errorf("unavailable: %w", err)
+		}
+		if len(ranges) == 0 {
+			return av, fmt.Errorf("unavailable[%s]: lista de rangos vacía (omitir el día si no aplica)", key)
+		}
+		for _, r := range ranges {
+			sr, err := parseSlotRange(r.From, r.To)
+			if err != nil {
+				return av, fmt.Errorf("unavailable[%s]: %w", key, err)
+			}
+			for _, d := range days {
+				av.Unavailable[d] = append(av.Unavailable[d], sr)
+			}
+		}
+	}
+
+	for key, ranges := range a.AvailableOnly {
+		days, err := parseDayKey(key)
+		if err != nil {
+			return av, fmt.Errorf("available_only: %w", err)
+		}
+		if len(ranges) == 0 {
+			return av, fmt.Errorf("available_only[%s]: lista de rangos vacía (para un día sin disponibilidad usar unavailable)", key)
+		}
+		for _, r := range ranges {
+			sr, err := parseSlotRange(r.From, r.To)
+			if err != nil {
+				return av, fmt.Errorf("available_only[%s]: %w", key, err)
+			}
+			for _, d := range days {
+				av.AvailableOnly[d] = append(av.AvailableOnly[d], sr)
+			}
+		}
+	}
+
+	for key, prefs := range a.Preferences {
+		days, err := parseDayKey(key)
+		if err != nil {
+			return av, fmt.Errorf("preferences: %w", err)
+		}
+		if len(prefs) == 0 {
+			return av, fmt.Errorf("preferences[%s]: lista de rangos vacía (omitir el día si no aplica)", key)
+		}
+		for _, p := range prefs {
+			sr, err := parseSlotRange(p.From, p.To)
+			if err != nil {
+				return av, fmt.Errorf("preferences[%s]: %w", key, err)
+			}
+			level := domain.PreferenceLevel(p.Level)
+			if level < domain.StronglyUndesired || level > domain.StronglyPreferred || level == domain.Neutral {
+				return av, fmt.Errorf("preferences[%s]: nivel %d fuera de rango (-2..-1, 1..2)", key, p.Level)
+			}
+			for _, d := range days {
+				av.Preferences[d] = append(av.Preferences[d], domain.SlotPreference{SlotRange: sr, Level: level})
+			}
+		}
+	}
+
+	return av, nil
+}
+
+// parseDayKey convierte una llave de día a la lista de días a los que aplica
+func parseDayKey(key string) ([]int, error) {
+	if strings.EqualFold(strings.TrimSpace(key), domain.AllDaysKey) {
+		days := make([]int, domain.DaysPerWeek)
+		for i := range days {
+			days[i] = i
+		}
+		return days, nil
+	}
+	day, err := domain.ParseDay(key)
+	if err != nil {
+		return nil, err
+	}
+	return []int{day}, nil
+}
+
+// parseSlotRange valida que el rango esté dentro de los bloques del día
+func parseSlotRange(from, to int) (domain.SlotRange, error) {
+	if from < 0 || to >= domain.BlocksPerDay || from > to {
+		return domain.SlotRange{}, fmt.Errorf("rango de slots inválido %d-%d (debe estar entre 0 y %d)", from, to, domain.BlocksPerDay-1)
+	}
+	return domain.SlotRange{From: from, To: to}, nil
+}
+
 // RoomConstraints mapea CourseCode -> EventType -> []AllowedRooms
 type RoomConstraints map[string]map[string][]string
 
@@ -395,3 +529,273 @@ func FilterRoomsByConstraint(rooms []domain.Room, allowedCodes []string) []domai
 	}
 	return filtered
 }
+
+// TimeGridJSON representa la grilla semanal de time_grid.json
+type TimeGridJSON struct {
+	Days           []GridDayJSON       `json:"days"`
+	Slots          []TimeSlotJSON      `json:"slots"`
+	BlockedPeriods []BlockedPeriodJSON `json:"blocked_periods"`
+}
+
+// GridDayJSON representa un día de la grilla, slots limita los bloques utilizables (0 = todos)
+type GridDayJSON struct {
+	Name  string `json:"name"`
+	Slots int    `json:"slots"`
+}
+
+// TimeSlotJSON representa un slot horario en formato HH:MM
+type TimeSlotJSON struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// BlockedPeriodJSON representa un periodo sin clases, majors vacío aplica a toda la institución
+type BlockedPeriodJSON struct {
+	Name   string   `json:"name"`
+	Day    string   `json:"day"`
+	From   int      `json:"from"`
+	To     int      `json:"to"`
+	Majors []string `json:"majors"`
+}
+
+// LoadTimeGrid lee time_grid.json y valida que los slots sean horarios crecientes y sin traslape
+func LoadTimeGrid(path string) (domain.TimeGrid, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.TimeGrid{}, err
+	}
+
+	var gridJSON TimeGridJSON
+	if err := decodeStrict(data, &gridJSON); err != nil {
+		return domain.TimeGrid{}, err
+	}
+
+	if len(gridJSON.Days) == 0 {
+		return domain.TimeGrid{}, fmt.Errorf("la grilla no tiene días")
+	}
+	if len(gridJSON.Slots) == 0 {
+		return domain.TimeGrid{}, fmt.Errorf("la grilla no tiene slots")
+	}
+
+	var grid domain.TimeGrid
+	seenDays := make(map[string]bool)
+	for i, d := range gridJSON.Days {
+		name := strings.TrimSpace(d.Name)
+		if name == "" {
+			return domain.TimeGrid{}, fmt.Errorf("día %d sin nombre", i)
+		}
+		if seenDays[strings.ToLower(name)] {
+			return domain.TimeGrid{}, fmt.Errorf("día %q repetido", name)
+		}
+		seenDays[strings.ToLower(name)] = true
+		if d.Slots < 0 || d.Slots > len(gridJSON.Slots) {
+			return domain.TimeGrid{}, fmt.Errorf("día %q: slots %d fuera de rango (0-%d)", name, d.Slots, len(gridJSON.Slots))
+		}
+		grid.Days = append(grid.Days, domain.GridDay{Name: name, Slots: d.Slots})
+	}
+
+	var prevEnd time.Time
+	for i, s := range gridJSON.Slots {
+		start, err := time.Parse("15:04", s.Start)
+		if err != nil {
+			return domain.TimeGrid{}, fmt.Errorf("slot %d: hora de inicio inválida %q", i, s.Start)
+		}
+		end, err := time.Parse("15:04", s.End)
+		if err != nil {
+			return domain.TimeGrid{}, fmt.Errorf("slot %d: hora de término inválida %q", i, s.End)
+		}
+		if !start.Before(end) {
+			return domain.TimeGrid{}, fmt.Errorf("slot %d: %s-%s termina antes de comenzar", i, s.Start, s.End)
+		}
+		if i > 0 && start.Before(prevEnd) {
+			return domain.TimeGrid{}, fmt.Errorf("slot %d: %s-%s se traslapa con el slot anterior", i, s.Start, s.End)
+		}
+		prevEnd = end
+		grid.Slots = append(grid.Slots, domain.TimeSlot{Start: s.Start, End: s.End})
+	}
+
+	for i, p := range gridJSON.BlockedPeriods {
+		day, err := grid.DayIndex(p.Day)
+		if err != nil {
+			return domain.TimeGrid{}, fmt.Errorf("periodo bloqueado %d (%s): %w", i, p.Name, err)
+		}
+		if p.From < 0 || p.To >= len(grid.Slots) || p.From > p.To {
+			return domain.TimeGrid{}, fmt.Errorf("periodo bloqueado %d (%s): rango de slots inválido %d-%d", i, p.Name, p.From, p.To)
+		}
+		grid.Blocked = append(grid.Blocked, domain.BlockedPeriod{
+			Name:   p.Name,
+			Day:    day,
+			From:   p.From,
+			To:     p.To,
+			Majors: p.Majors,
+		})
+	}
+
+	return grid, nil
+}
+
+// WeightJSON es un peso de la función objetivo: un número, o un nivel de penalización
+// ("hard", "medium", "soft") opcionalmente negado con "-" para usarlo como bono
+type WeightJSON float64
+
+// UnmarshalJSON acepta números o nombres de nivel de penalización
+func (w *WeightJSON) UnmarshalJSON(data []byte) error {
+	var num float64
+	if err := json.Unmarshal(data, &num); err == nil {
+		*w = WeightJSON(num)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("peso inválido %s (usar un número o \"hard\", \"medium\", \"soft\")", data)
+	}
+	sign := 1.0
+	level := strings.ToLower(strings.TrimSpace(name))
+	if strings.HasPrefix(level, "-") {
+		sign = -1
+		level = strings.TrimSpace(level[1:])
+	}
+	switch level {
+	case "hard":
+		*w = WeightJSON(sign * domain.PenaltyHard)
+	case "medium":
+		*w = WeightJSON(sign * domain.PenaltyMedium)
+	case "soft":
+		*w = WeightJSON(sign * domain.PenaltySoft)
+	default:
+		return fmt.Errorf("nivel de penalización desconocido %q (usar \"hard\", \"medium\" o \"soft\")", name)
+	}
+	return nil
+}
+
+// ObjectiveWeightsJSON representa objective_weights.json, los términos omitidos mantienen su valor por defecto
+type ObjectiveWeightsJSON struct {
+	Hard               *WeightJSON `json:"hard"`
+	Mirror             *WeightJSON `json:"mirror"`
+	SiblingRoom        *WeightJSON `json:"sibling_room"`
+	DaySeparation0     *WeightJSON `json:"day_separation_0"`
+	DaySeparation1     *WeightJSON `json:"day_separation_1"`
+	DaySeparation2     *WeightJSON `json:"day_separation_2"`
+	DaySeparation3     *WeightJSON `json:"day_separation_3"`
+	DaySeparationOther *WeightJSON `json:"day_separation_other"`
+	MultiCATSameDay    *WeightJSON `json:"multi_cat_same_day"`
+	MultiCATNextDay    *WeightJSON `json:"multi_cat_next_day"`
+	CATSameDayAsAY     *WeightJSON `json:"cat_same_day_as_ay"`
+	AYNotWednesday     *WeightJSON `json:"ay_not_wednesday"`
+	PrereqSameBlock    *WeightJSON `json:"prereq_same_block"`
+	TeacherPreference  *WeightJSON `json:"teacher_preference"`
+	MovedBlock         *WeightJSON `json:"moved_block"`
+	MovedRoom          *WeightJSON `json:"moved_room"`
+}
+
+// LoadObjectiveWeights lee objective_weights.json sobre los pesos por defecto. Rechaza términos desconocidos
+// para que un nombre mal escrito no se ignore en silencio.
+func LoadObjectiveWeights(path string) (domain.ObjectiveWeights, error) {
+	weights := domain.DefaultObjectiveWeights()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weights, err
+	}
+
+	var wj ObjectiveWeightsJSON
+	if err := decodeStrict(data, &wj); err != nil {
+		return weights, err
+	}
+
+	for _, t := range []struct {
+		src *WeightJSON
+		dst *float64
+	}{
+		{wj.Hard, &weights.Hard},
+		{wj.Mirror, &weights.Mirror},
+		{wj.SiblingRoom, &weights.SiblingRoom},
+		{wj.DaySeparation0, &weights.DaySeparation0},
+		{wj.DaySeparation1, &weights.DaySeparation1},
+		{wj.DaySeparation2, &weights.DaySeparation2},
+		{wj.DaySeparation3, &weights.DaySeparation3},
+		{wj.DaySeparationOther, &weights.DaySeparationOther},
+		{wj.MultiCATSameDay, &weights.MultiCATSameDay},
+		{wj.MultiCATNextDay, &weights.MultiCATNextDay},
+		{wj.CATSameDayAsAY, &weights.CATSameDayAsAY},
+		{wj.AYNotWednesday, &weights.AYNotWednesday},
+		{wj.PrereqSameBlock, &weights.PrereqSameBlock},
+		{wj.TeacherPreference, &weights.TeacherPreference},
+		{wj.MovedBlock, &weights.MovedBlock},
+		{wj.MovedRoom, &weights.MovedRoom},
+	} {
+		if t.src != nil {
+			*t.dst = float64(*t.src)
+		}
+	}
+
+	if weights.Hard <= 0 {
+		return weights, fmt.Errorf("hard debe ser positivo (es %.0f)", weights.Hard)
+	}
+	return weights, nil
+}
+
+// SemesterCalendarJSON es el formato de semester_calendar.json, las fechas en formato AAAA-MM-DD
+type SemesterCalendarJSON struct {
+	Start    string        `json:"start"`
+	End      string        `json:"end"`
+	TimeZone string        `json:"timezone"`
+	Holidays []HolidayJSON `json:"holidays"`
+}
+
+// HolidayJSON es un feriado de un día (date) o un rango de días (date a to)
+type HolidayJSON struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+	To   string `json:"to"`
+}
+
+// LoadSemesterCalendar lee semester_calendar.json: fechas del semestre, zona horaria y feriados
+func LoadSemesterCalendar(path string) (domain.SemesterCalendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.SemesterCalendar{}, err
+	}
+
+	var calJSON SemesterCalendarJSON
+	if err := decodeStrict(data, &calJSON); err != nil {
+		return domain.SemesterCalendar{}, err
+	}
+
+	const dateLayout = "2006-01-02"
+	cal := domain.SemesterCalendar{TimeZone: strings.TrimSpace(calJSON.TimeZone)}
+	if cal.Start, err = time.Parse(dateLayout, calJSON.Start); err != nil {
+		return domain.SemesterCalendar{}, fmt.Errorf("fecha de inicio inválida %q (usar AAAA-MM-DD)", calJSON.Start)
+	}
+	if cal.End, err = time.Parse(dateLayout, calJSON.End); err != nil {
+		return domain.SemesterCalendar{}, fmt.Errorf("fecha de término inválida %q (usar AAAA-MM-DD)", calJSON.End)
+	}
+	if cal.End.Before(cal.Start) {
+		return domain.SemesterCalendar{}, fmt.Errorf("el semestre termina (%s) antes de comenzar (%s)", calJSON.End, calJSON.Start)
+	}
+	if cal.TimeZone != "" {
+		if _, err := time.LoadLocation(cal.TimeZone); err != nil {
+			return domain.SemesterCalendar{}, fmt.Errorf("zona horaria desconocida %q", cal.TimeZone)
+		}
+	}
+
+	for i, h := range calJSON.Holidays {
+		from, err := time.Parse(dateLayout, h.Date)
+		if err != nil {
+			return domain.SemesterCalendar{}, fmt.Errorf("feriado %d (%s): fecha inválida %q", i, h.Name, h.Date)
+		}
+		to := from
+		if h.To != "" {
+			if to, err = time.Parse(dateLayout, h.To); err != nil {
+				return domain.SemesterCalendar{}, fmt.Errorf("feriado %d (%s): fecha de término inválida %q", i, h.Name, h.To)
+			}
+			if to.Before(from) {
+				return domain.SemesterCalendar{}, fmt.Errorf("feriado %d (%s): termina antes de comenzar", i, h.Name)
+			}
+		}
+		cal.Holidays = append(cal.Holidays, domain.Holiday{Name: h.Name, From: from, To: to})
+	}
+
+	return cal, nil
+}
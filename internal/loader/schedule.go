@@ -0,0 +1,178 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// ScheduleJSON es la parte de schedule.json que se necesita para reconstruir un horario: la forma de la grilla
+// y las actividades con su bloque y sala. El resto del archivo (resumen, vista por día) se ignora.
+type ScheduleJSON struct {
+	Grid       ScheduleGridJSON       `json:"grid"`
+	Activities []ScheduleActivityJSON `json:"activities"`
+}
+
+// ScheduleGridJSON es la forma de la grilla con que se exportó el horario
+type ScheduleGridJSON struct {
+	Days         []string `json:"days"`
+	BlocksPerDay int      `json:"blocks_per_day"`
+}
+
+// ScheduleActivityJSON representa una actividad de schedule.json
+type ScheduleActivityJSON struct {
+	Code       string   `json:"code"`
+	CourseCode string   `json:"course_code"`
+	CourseName string   `json:"course_name"`
+	Type       string   `json:"type"`
+	Room       string   `json:"room"`
+	Block      int      `json:"block"`
+	Duration   int      `json:"duration"`
+	Students   int      `json:"students"`
+	Teachers   []string `json:"teachers"`
+	Sections   []int    `json:"sections"`
+}
+
+// ImportScheduleFromJSON lee un schedule.json (el que escribe exporter.ExportScheduleToJSON) y reconstruye las actividades con su
+// bloque y sala, para validarlo, re-optimizarlo, compararlo o editarlo sin ejecutar el pipeline completo.
+// Los IDs siguen el orden del archivo y las cátedras recuperan su SiblingGroupID, así SA mantiene el espejo.
+// La grilla activa ya debe estar configurada y tener la misma forma que la del archivo.
+func ImportScheduleFromJSON(filename string) ([]domain.Activity, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var schedule ScheduleJSON
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, err
+	}
+	return importSchedule(schedule)
+}
+
+// importSchedule convierte las actividades exportadas al modelo del dominio
+func importSchedule(schedule ScheduleJSON) ([]domain.Activity, error) {
+	// Los bloques solo significan lo mismo si la grilla tiene la misma forma
+	if g := schedule.Grid; g.BlocksPerDay != 0 && (g.BlocksPerDay != domain.BlocksPerDay || len(g.Days) != domain.DaysPerWeek) {
+		return nil, fmt.Errorf("el horario usa una grilla de %d días x %d bloques y la activa es de %d x %d",
+			len(g.Days), g.BlocksPerDay, domain.DaysPerWeek, domain.BlocksPerDay)
+	}
+
+	activities := make([]domain.Activity, 0, len(schedule.Activities))
+	seen := make(map[string]bool, len(schedule.Activities))
+	for i, ae := range schedule.Activities {
+		if ae.Code == "" {
+			return nil, fmt.Errorf("actividad %d sin código", i+1)
+		}
+		if seen[ae.Code] {
+			return nil, fmt.Errorf("actividad %s repetida", ae.Code)
+		}
+		seen[ae.Code] = true
+
+		eventType := domain.EventCategory(ae.Type)
+		if eventType != domain.CAT && eventType != domain.AY && eventType != domain.LAB {
+			return nil, fmt.Errorf("actividad %s: tipo desconocido %q", ae.Code, ae.Type)
+		}
+		if ae.Block >= domain.TotalBlocks {
+			return nil, fmt.Errorf("actividad %s: bloque %d fuera de la grilla (%d bloques)", ae.Code, ae.Block, domain.TotalBlocks)
+		}
+
+		siblingGroup := ""
+		if eventType == domain.CAT {
+			siblingGroup = SiblingGroupID(ae.CourseCode, ae.Sections)
+		}
+		a := domain.NewActivity(i+1, ae.Code, ae.CourseCode, ae.CourseName, eventType, eventNumber(ae.Code), ae.Sections, ae.Students, ae.Teachers, siblingGroup, ae.Duration)
+		if ae.Block >= 0 {
+			a.Block = ae.Block
+			a.Room = ae.Room
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+// eventNumber recupera el número de evento del código de la sesión ("CBE2000-CAT-2-S1" -> 2), 0 si no lo tiene
+func eventNumber(code string) int {
+	parts := strings.Split(code, "-")
+	if len(parts) < 3 {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ScheduleMatch resume cómo se aplicó un horario importado a las actividades de las entradas
+type ScheduleMatch struct {
+	Matched int      // actividades que recibieron el bloque y la sala del horario
+	New     []string // actividades de las entradas que no están en el horario (quedan sin programar)
+	Removed []string // actividades del horario que ya no están en las entradas
+	Changed []string // actividades emparejadas cuyos datos cambiaron (profesores, secciones, estudiantes, tipo o duración)
+}
+
+// ApplySchedule copia el bloque y la sala de un horario importado (p. ej. el del semestre anterior) a las
+// actividades cargadas de las entradas, emparejándolas por código.
+func ApplySchedule(activities []domain.Activity, schedule []domain.Activity) ScheduleMatch {
+	byCode := make(map[string]*domain.Activity, len(schedule))
+	for i := range schedule {
+		byCode[schedule[i].Code] = &schedule[i]
+	}
+
+	var match ScheduleMatch
+	used := make(map[string]bool, len(activities))
+	for i := range activities {
+		a := &activities[i]
+		prev, ok := byCode[a.Code]
+		if !ok {
+			a.Block, a.Room = -1, ""
+			match.New = append(match.New, a.Code)
+			continue
+		}
+		a.Block, a.Room = prev.Block, prev.Room
+		used[a.Code] = true
+		match.Matched++
+		if activityDataChanged(a, prev) {
+			match.Changed = append(match.Changed, a.Code)
+		}
+	}
+	for code := range byCode {
+		if !used[code] {
+			match.Removed = append(match.Removed, code)
+		}
+	}
+	sort.Strings(match.New)
+	sort.Strings(match.Removed)
+	sort.Strings(match.Changed)
+	return match
+}
+
+// activityDataChanged compara los datos de una actividad que afectan dónde se puede programar
+func activityDataChanged(a, prev *domain.Activity) bool {
+	if a.Type != prev.Type || a.Students != prev.Students || a.Span() != prev.Span() {
+		return true
+	}
+	if len(a.Sections) != len(prev.Sections) || len(a.TeacherNames) != len(prev.TeacherNames) {
+		return true
+	}
+	for i := range a.Sections {
+		if a.Sections[i] != prev.Sections[i] {
+			return true
+		}
+	}
+	teachers := make(map[string]bool, len(prev.TeacherNames))
+	for _, t := range prev.TeacherNames {
+		teachers[t] = true
+	}
+	for _, t := range a.TeacherNames {
+		if !teachers[t] {
+			return true
+		}
+	}
+	return false
+}
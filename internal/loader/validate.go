@@ -0,0 +1,497 @@
+package loader
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// Severity indica la gravedad de un problema encontrado en las entradas
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"       // el horario generado sería incorrecto o incompleto
+	SeverityWarning Severity = "ADVERTENCIA" // el loader lo tolera usando un valor por defecto
+)
+
+// Issue es un problema encontrado en un archivo de entrada, con el registro que lo causa
+type Issue struct {
+	Severity Severity
+	File     string
+	Record   string // registro dentro del archivo ("actividad id=12 (CBF1000-CAT-1)", "línea 5", ...)
+	Message  string
+}
+
+func (i Issue) String() string {
+	if i.Record == "" {
+		return fmt.Sprintf("[%s] %s: %s", i.Severity, i.File, i.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s: %s", i.Severity, i.File, i.Record, i.Message)
+}
+
+// HasErrors indica si alguno de los problemas es un error
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// InputFiles agrupa las rutas de los archivos de entrada a validar
+type InputFiles struct {
+	Oferta          string
+	Courses         string
+	Rooms           string
+	Teachers        string
+	RoomConstraints string
+}
+
+// defaultsKey es la entrada de rooms_constraints.json que documenta la asignación por tipo de sala
+const defaultsKey = "DEFAULTS"
+
+// inputValidator acumula los problemas encontrados y los datos leídos para los cruces entre archivos
+type inputValidator struct {
+	files  InputFiles
+	issues []Issue
+
+	courses     map[string]CourseFullJSON // código -> curso de courses.json (nil si no se pudo leer)
+	rooms       map[string]domain.Room    // código -> sala válida de rooms.csv (nil si no se pudo leer)
+	teachers    map[string]bool           // nombres en profesores.json (nil si no se pudo leer)
+	constraints RoomConstraints
+}
+
+// ValidateInputs revisa los archivos de entrada y retorna todos los problemas encontrados, incluyendo los que
+// los loaders toleran en silencio (valores por defecto, referencias que se ignoran). La grilla activa debe estar
+// configurada antes con domain.SetTimeGrid.
+func ValidateInputs(files InputFiles) []Issue {
+	v := &inputValidator{files: files}
+	v.validateCourses()
+	v.validateRooms()
+	v.validateTeachers()
+	v.validateRoomConstraints()
+	v.validateOferta()
+	return v.issues
+}
+
+func (v *inputValidator) add(sev Severity, file, record, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Severity: sev,
+		File:     file,
+		Record:   record,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// readJSON lee y decodifica un archivo JSON, reportando el error si falla
+func (v *inputValidator) readJSON(path string, dst interface{}) bool {
+	return v.decodeJSON(path, dst, json.Unmarshal)
+}
+
+// readStrictJSON es readJSON rechazando campos desconocidos, como el loader del archivo
+func (v *inputValidator) readStrictJSON(path string, dst interface{}) bool {
+	return v.decodeJSON(path, dst, decodeStrict)
+}
+
+func (v *inputValidator) decodeJSON(path string, dst interface{}, decode func([]byte, interface{}) error) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		v.add(SeverityError, path, "", "no se pudo leer: %v", err)
+		return false
+	}
+	if err := decode(data, dst); err != nil {
+		v.add(SeverityError, path, "", "JSON inválido: %v", err)
+		return false
+	}
+	return true
+}
+
+// validateCourses revisa courses.json: IDs y códigos repetidos, prerrequisitos y distribución
+func (v *inputValidator) validateCourses() {
+	path := v.files.Courses
+
+	type courseJSON struct {
+		CourseFullJSON
+		Prerequisites []int `json:"Prerequisites"`
+	}
+	var courses []courseJSON
+	if !v.readJSON(path, &courses) {
+		return
+	}
+
+	v.courses = make(map[string]CourseFullJSON)
+	ids := make(map[int]string)
+	for _, c := range courses {
+		if prev, ok := ids[c.ID]; ok {
+			v.add(SeverityError, path, courseRecord(c.ID, c.Code), "ID repetido, también lo usa %s", prev)
+		} else {
+			ids[c.ID] = c.Code
+		}
+		if c.Code == "" {
+			v.add(SeverityError, path, courseRecord(c.ID, c.Code), "curso sin código")
+			continue
+		}
+		if _, ok := v.courses[c.Code]; ok {
+			v.add(SeverityError, path, courseRecord(c.ID, c.Code), "código repetido, los prerrequisitos y la distribución se tomarían del último")
+		}
+		v.courses[c.Code] = c.CourseFullJSON
+	}
+
+	maxSlots := maxSlotsPerDay()
+	for _, c := range courses {
+		rec := courseRecord(c.ID, c.Code)
+		for _, p := range c.Prerequisites {
+			if _, ok := ids[p]; !ok {
+				v.add(SeverityWarning, path, rec, "prerrequisito con ID %d no existe, se ignora", p)
+			}
+		}
+
+		if len(c.PlanLocation) == 0 && !c.IsElective {
+			v.add(SeverityWarning, path, rec, "sin PlanLocation, el curso no participa en cliques de semestre")
+		}
+		for major, sem := range c.PlanLocation {
+			if sem <= 0 {
+				v.add(SeverityError, path, rec, "semestre %d inválido para la carrera %s", sem, major)
+			}
+		}
+
+		d := c.Distribution
+		for _, s := range []struct {
+			name          string
+			num, duration int
+		}{
+			{"CAT", d.NumCAT, d.DurationCAT},
+			{"AY", d.NumAY, d.DurationAY},
+			{"LAB", d.NumLAB, d.DurationLAB},
+		} {
+			if s.num < 0 || s.duration < 0 {
+				v.add(SeverityError, path, rec, "Num%s=%d y Duration%s=%d no pueden ser negativos", s.name, s.num, s.name, s.duration)
+				continue
+			}
+			if s.num > 0 && s.duration == 0 {
+				v.add(SeverityWarning, path, rec, "Num%s=%d sin Duration%s, se asume 1 bloque", s.name, s.num, s.name)
+			}
+			if s.duration > maxSlots {
+				v.add(SeverityError, path, rec, "Duration%s=%d no cabe en ningún día de la grilla (máximo %d bloques)", s.name, s.duration, maxSlots)
+			}
+		}
+	}
+}
+
+// validateRooms revisa rooms.csv: columnas, capacidades y códigos repetidos
+func (v *inputValidator) validateRooms() {
+	path := v.files.Rooms
+
+	file, err := os.Open(path)
+	if err != nil {
+		v.add(SeverityError, path, "", "no se pudo leer: %v", err)
+		return
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // se reportan las líneas mal formadas en vez de abortar
+	records, err := reader.ReadAll()
+	if err != nil {
+		v.add(SeverityError, path, "", "CSV inválido: %v", err)
+		return
+	}
+
+	v.rooms = make(map[string]domain.Room)
+	for i, record := range records {
+		if i == 0 { // encabezado
+			continue
+		}
+		rec := fmt.Sprintf("línea %d", i+1)
+		if len(record) < 2 {
+			v.add(SeverityError, path, rec, "se esperaban 2 columnas (Sala,Capacidad), hay %d; la sala se ignora", len(record))
+			continue
+		}
+		code := record[0]
+		if code == "" {
+			v.add(SeverityError, path, rec, "sala sin código")
+			continue
+		}
+		capacity, err := strconv.Atoi(record[1])
+		if err != nil {
+			v.add(SeverityError, path, rec, "capacidad %q de la sala %s no es un número", record[1], code)
+			continue
+		}
+		if capacity <= 0 {
+			v.add(SeverityError, path, rec, "capacidad %d de la sala %s debe ser positiva", capacity, code)
+			continue
+		}
+		if _, ok := v.rooms[code]; ok {
+			v.add(SeverityError, path, rec, "sala %s repetida", code)
+			continue
+		}
+		v.rooms[code] = domain.Room{ID: i, Code: code, Capacity: capacity, Type: roomTypeFromCode(code)}
+	}
+}
+
+// validateTeachers revisa profesores.json: IDs y nombres repetidos y disponibilidad
+func (v *inputValidator) validateTeachers() {
+	path := v.files.Teachers
+
+	var teachers []TeacherJSON
+	if !v.readStrictJSON(path, &teachers) {
+		return
+	}
+
+	v.teachers = make(map[string]bool)
+	ids := make(map[int]string)
+	for _, t := range teachers {
+		rec := fmt.Sprintf("profesor id=%d (%s)", t.ID, t.Name)
+		if prev, ok := ids[t.ID]; ok {
+			v.add(SeverityError, path, rec, "ID repetido, también lo usa %q", prev)
+		} else {
+			ids[t.ID] = t.Name
+		}
+		if t.Name == "" {
+			v.add(SeverityError, path, rec, "profesor sin nombre")
+			continue
+		}
+		if v.teachers[t.Name] {
+			v.add(SeverityError, path, rec, "nombre repetido, la disponibilidad se toma de uno solo")
+		}
+		v.teachers[t.Name] = true
+
+		if len(t.UnavailableBlocks) > 0 {
+			v.add(SeverityError, path, rec, "el campo unavailable_blocks ya no se soporta, usar availability")
+		}
+		if _, err := parseAvailability(t.Availability); err != nil {
+			v.add(SeverityError, path, rec, "%v", err)
+		}
+	}
+}
+
+// validateRoomConstraints revisa que rooms_constraints.json solo referencie cursos, tipos y salas conocidos
+func (v *inputValidator) validateRoomConstraints() {
+	path := v.files.RoomConstraints
+
+	if !v.readJSON(path, &v.constraints) {
+		v.constraints = nil
+		return
+	}
+
+	for _, course := range sortedKeys(v.constraints) {
+		if course == defaultsKey {
+			continue
+		}
+		if v.courses != nil {
+			if _, ok := v.courses[course]; !ok {
+				v.add(SeverityWarning, path, course, "curso no existe en %s, la restricción no se usa", v.files.Courses)
+			}
+		}
+		byType := v.constraints[course]
+		for _, eventType := range sortedKeys(byType) {
+			rec := course + "/" + eventType
+			if !isKnownEventType(eventType) {
+				v.add(SeverityError, path, rec, "tipo de evento desconocido (usar CATEDRA, AYUDANTIA o LABORATORIO)")
+				continue
+			}
+			allowed := byType[eventType]
+			if len(allowed) == 0 {
+				v.add(SeverityError, path, rec, "lista de salas vacía, ninguna actividad podría recibir sala")
+				continue
+			}
+			if v.rooms == nil {
+				continue
+			}
+			for _, code := range allowed {
+				if _, ok := v.rooms[code]; !ok {
+					v.add(SeverityError, path, rec, "sala %q no existe en %s", code, v.files.Rooms)
+				}
+			}
+		}
+	}
+}
+
+// validateOferta revisa oferta_academica.json y sus referencias a cursos, profesores y salas
+func (v *inputValidator) validateOferta() {
+	path := v.files.Oferta
+
+	var oferta []CourseOfertaJSON
+	if !v.readJSON(path, &oferta) {
+		return
+	}
+
+	courseSeen := make(map[string]bool)
+	ids := make(map[int]string)
+	codes := make(map[string]bool)
+	missingTeachers := make(map[string]string) // nombre -> primera actividad que lo referencia
+
+	for _, c := range oferta {
+		course, known := v.courses[c.CourseCode]
+		if courseSeen[c.CourseCode] {
+			v.add(SeverityError, path, "curso "+c.CourseCode, "curso repetido en la oferta")
+		}
+		courseSeen[c.CourseCode] = true
+		if v.courses != nil && !known {
+			v.add(SeverityError, path, "curso "+c.CourseCode,
+				"no existe en %s: sus actividades se cargarían con 1 sesión de 1 bloque y sin cliques de semestre", v.files.Courses)
+		}
+
+		for _, a := range c.Activities {
+			rec := fmt.Sprintf("actividad id=%d (%s)", a.ID, a.ActivityCode)
+
+			if prev, ok := ids[a.ID]; ok {
+				v.add(SeverityError, path, rec, "ID repetido, también lo usa %s", prev)
+			} else {
+				ids[a.ID] = a.ActivityCode
+			}
+			if codes[a.ActivityCode] {
+				v.add(SeverityError, path, rec, "activity_code repetido, los códigos de sesión se duplicarían")
+			}
+			codes[a.ActivityCode] = true
+
+			if !isKnownEventType(a.Type) {
+				v.add(SeverityError, path, rec, "tipo %q desconocido, se cargaría como CATEDRA", a.Type)
+			}
+			eventType := parseEventCategory(a.Type)
+
+			if len(a.LinkedSections) == 0 {
+				v.add(SeverityWarning, path, rec, "sin linked_sections")
+			}
+			if a.TotalStudents <= 0 {
+				v.add(SeverityWarning, path, rec, "total_students=%d", a.TotalStudents)
+			}
+
+			if known {
+				num, _ := distributionFor(course.Distribution, eventType)
+				if num == 0 {
+					v.add(SeverityWarning, path, rec, "%s no define Num%s para %s, se asume 1 sesión de 1 bloque",
+						v.files.Courses, eventTypeSuffix(eventType), c.CourseCode)
+				}
+			}
+
+			if v.teachers != nil {
+				for _, name := range a.Teachers {
+					if !v.teachers[name] {
+						if _, ok := missingTeachers[name]; !ok {
+							missingTeachers[name] = rec
+						}
+					}
+				}
+			}
+
+			v.checkRoomFit(path, rec, c.CourseCode, eventType, a.TotalStudents)
+		}
+	}
+
+	// Un aviso por profesor, indicando la primera actividad donde aparece
+	for _, name := range sortedKeys(missingTeachers) {
+		v.add(SeverityWarning, path, missingTeachers[name],
+			"profesor %q no existe en %s, su disponibilidad no se considera", name, v.files.Teachers)
+	}
+}
+
+// checkRoomFit verifica que exista alguna sala permitida donde quepan los estudiantes de la actividad,
+// con el mismo criterio que usan el scheduler y SA (restricción explícita o tipo de sala)
+func (v *inputValidator) checkRoomFit(path, rec, courseCode string, eventType domain.EventCategory, students int) {
+	if v.rooms == nil {
+		return
+	}
+
+	allowed := v.constraints.GetAllowedRooms(courseCode, string(eventType))
+	best := domain.Room{}
+	candidates := 0
+	for _, code := range sortedKeys(v.rooms) {
+		r := v.rooms[code]
+		if allowed != nil {
+			if !containsString(allowed, r.Code) {
+				continue
+			}
+		} else if (eventType == domain.LAB) != (r.Type == domain.RoomLab) {
+			continue
+		}
+		candidates++
+		if r.Capacity > best.Capacity {
+			best = r
+		}
+	}
+
+	if candidates == 0 {
+		v.add(SeverityError, path, rec, "no hay salas permitidas para %s %s", courseCode, string(eventType))
+		return
+	}
+	if students > best.Capacity {
+		v.add(SeverityError, path, rec, "%d estudiantes no caben en ninguna sala permitida (la mayor es %s con %d)",
+			students, best.Code, best.Capacity)
+	}
+}
+
+// distributionFor retorna sesiones y duración de la distribución para un tipo de evento
+func distributionFor(d DistributionJSON, eventType domain.EventCategory) (int, int) {
+	switch eventType {
+	case domain.AY:
+		return d.NumAY, d.DurationAY
+	case domain.LAB:
+		return d.NumLAB, d.DurationLAB
+	default:
+		return d.NumCAT, d.DurationCAT
+	}
+}
+
+func eventTypeSuffix(eventType domain.EventCategory) string {
+	switch eventType {
+	case domain.AY:
+		return "AY"
+	case domain.LAB:
+		return "LAB"
+	default:
+		return "CAT"
+	}
+}
+
+func isKnownEventType(s string) bool {
+	return s == string(domain.CAT) || s == string(domain.AY) || s == string(domain.LAB)
+}
+
+// roomTypeFromCode deduce el tipo de sala desde su código, igual que LoadRooms
+func roomTypeFromCode(code string) domain.RoomType {
+	if strings.HasPrefix(code, "LAB") {
+		return domain.RoomLab
+	}
+	return domain.RoomClassroom
+}
+
+// maxSlotsPerDay retorna la mayor cantidad de bloques utilizables en un día de la grilla activa
+func maxSlotsPerDay() int {
+	max := 0
+	for d := 0; d < domain.DaysPerWeek; d++ {
+		if s := domain.SlotsInDay(d); s > max {
+			max = s
+		}
+	}
+	return max
+}
+
+func courseRecord(id int, code string) string {
+	return fmt.Sprintf("curso id=%d (%s)", id, code)
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
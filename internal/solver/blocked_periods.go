@@ -0,0 +1,24 @@
+package solver
+
+import (
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// buildCourseMajors crea un índice de carreras por curso a partir de PlanLocation
+func buildCourseMajors(planLocations map[string]map[string]int) map[string][]string {
+	index := make(map[string][]string)
+	for code, locs := range planLocations {
+		for major := range locs {
+			index[code] = append(index[code], major)
+		}
+		sort.Strings(index[code])
+	}
+	return index
+}
+
+// isBlockedForActivity verifica si la actividad ocuparía un periodo bloqueado para la institución o para sus carreras
+func isBlockedForActivity(activity *domain.Activity, block int, courseMajors map[string][]string) bool {
+	return domain.OccupiesBlockedPeriod(block, activity.Duration, courseMajors[activity.CourseCode])
+}
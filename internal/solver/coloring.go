@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"math/rand"
 	"sort"
 
 	"timetabling-UDP/internal/domain"
@@ -13,184 +14,188 @@ type ColorSet struct {
 	Activities []*domain.Activity // Actividades asignadas a este periodo
 }
 
-// GreedyColoring implementa el algoritmo de Dutton-Brigham para colorear el grafo.
+// GreedyColoring colorea el grafo con la estrategia indicada (Dutton-Brigham si es nil).
 // Retorna una lista de ColorSets donde cada set es un periodo sin conflictos.
-func GreedyColoring(g *graph.ConflictGraph) []ColorSet {
-	// Crear copia de trabajo del grafo
-	H := cloneGraph(g)
+func GreedyColoring(g *graph.ConflictGraph, strategy ColoringStrategy) []ColorSet {
+	if strategy == nil {
+		strategy = DuttonBrigham{}
+	}
 
 	var colorSets []ColorSet
-	color := 0
-
-	// Mientras queden vértices sin colorear
-	for H.NumVertices() > 0 {
-		// Encontrar conjunto independiente máximo
-		colorSet := findMaxIndependentSet(H)
-
-		if len(colorSet) == 0 {
-			break
-		}
-
+	for color, class := range strategy.Color(g, nil) {
 		// Crear ColorSet con las actividades
 		cs := ColorSet{
 			Color:      color,
-			Activities: make([]*domain.Activity, len(colorSet)),
+			Activities: make([]*domain.Activity, len(class)),
 		}
-		for i, id := range colorSet {
+		for i, id := range class {
 			cs.Activities[i] = g.Vertices[id]
 		}
 		colorSets = append(colorSets, cs)
-
-		// Eliminar vértices coloreados del grafo de trabajo
-		for _, id := range colorSet {
-			removeVertex(H, id)
-		}
-
-		color++
 	}
 
 	return colorSets
 }
 
-// findMaxIndependentSet encuentra un conjunto independiente máximo
-func findMaxIndependentSet(H *graph.ConflictGraph) []int {
+// findMaxIndependentSet encuentra un conjunto independiente máximo con la heurística de Dutton-Brigham.
+// Los vértices se recorren por ID y los empates se deciden con rng (si es nil gana el menor ID),
+// así el resultado es reproducible.
+func findMaxIndependentSet(H *graph.ConflictGraph, rng *rand.Rand) []int {
 	if H.NumVertices() == 0 {
 		return nil
 	}
 
-	seed := maxDegreeVertex(H)
+	seed := maxDegreeVertex(H, rng)
 	if seed == -1 {
 		return nil
 	}
 
-	independentSet := []int{seed}
-	merged := map[int]bool{seed: true}
-
-	for {
-		// Buscar mejor candidato para fusionar (máximos vecinos comunes, no adyacente)
-		candidate := findBestMergeCandidate(H, independentSet, merged)
-
-		if candidate == -1 {
-			// No hay más candidatos, el conjunto está completo
-			break
-		}
+	ids := H.VertexIDs()
+	independentSet := []int{}
+	merged := map[int]bool{}
+	// setNeighbors cuenta a cuántos vértices del conjunto es adyacente cada vértice
+	setNeighbors := make(map[int]int)
 
+	candidate := seed
+	for candidate != -1 {
 		// Agregar al conjunto independiente
 		independentSet = append(independentSet, candidate)
 		merged[candidate] = true
+		for n := range H.Adjacency[candidate] {
+			setNeighbors[n]++
+		}
+
+		// Buscar mejor candidato para fusionar (máximos vecinos comunes, no adyacente)
+		candidate = findBestMergeCandidate(H, ids, merged, setNeighbors, rng)
 	}
 
 	return independentSet
 }
 
 // findBestMergeCandidate encuentra el vértice no adyacente con más vecinos comunes.
-func findBestMergeCandidate(H *graph.ConflictGraph, currentSet []int, merged map[int]bool) int {
+func findBestMergeCandidate(H *graph.ConflictGraph, ids []int, merged map[int]bool, setNeighbors map[int]int, rng *rand.Rand) int {
 	bestCandidate := -1
 	maxCommonNeighbors := -1
+	ties := newTieBreaker(rng)
 
 	// Para cada candidato
-	for candidateID := range H.Vertices {
+	for _, candidateID := range ids {
 		// continuar si ya está en el conjunto
 		if merged[candidateID] {
 			continue
 		}
 
 		// Verificar que NO sea adyacente a ningún vértice del conjunto actual
-		isAdjacent := false
-		for _, setID := range currentSet {
-			if H.HasEdge(candidateID, setID) {
-				isAdjacent = true
-				break
-			}
-		}
-		if isAdjacent {
+		if setNeighbors[candidateID] > 0 {
 			continue
 		}
 
 		// Contar vecinos comunes con el conjunto
-		commonNeighbors := countCommonNeighbors(H, currentSet, candidateID)
+		commonNeighbors := countCommonNeighbors(H, setNeighbors, candidateID)
 
 		// Elegir el candidato con más vecinos comunes
 		if commonNeighbors > maxCommonNeighbors {
 			maxCommonNeighbors = commonNeighbors
 			bestCandidate = candidateID
+			ties.reset()
 		} else if commonNeighbors == maxCommonNeighbors && bestCandidate != -1 {
 			// si hay empate, elegir el de mayor grado
 			if H.Degree(candidateID) > H.Degree(bestCandidate) {
 				bestCandidate = candidateID
+				ties.reset()
+			} else if H.Degree(candidateID) == H.Degree(bestCandidate) && ties.take() {
+				bestCandidate = candidateID
 			}
 		}
 	}
 
 	// Si no hay candidatos con vecinos comunes, buscar cualquier vertice no adyacente con max grado
 	if bestCandidate == -1 {
-		bestCandidate = findMaxDegreeNonAdjacent(H, currentSet, merged)
+		bestCandidate = findMaxDegreeNonAdjacent(H, ids, merged, setNeighbors, rng)
 	}
 
 	return bestCandidate
 }
 
-// countCommonNeighbors cuenta cuántos vecinos del conjunto son también vecinos del candidato.
-func countCommonNeighbors(H *graph.ConflictGraph, currentSet []int, candidateID int) int {
-	candidateNeighbors := make(map[int]bool)
-	for _, n := range H.Neighbors(candidateID) {
-		candidateNeighbors[n] = true
-	}
-
+// countCommonNeighbors cuenta cuántos vecinos del conjunto son también vecinos del candidato,
+// sumando por cada vecino del candidato a cuántos vértices del conjunto es adyacente.
+func countCommonNeighbors(H *graph.ConflictGraph, setNeighbors map[int]int, candidateID int) int {
 	count := 0
-	for _, setID := range currentSet {
-		for _, neighbor := range H.Neighbors(setID) {
-			if candidateNeighbors[neighbor] {
-				count++
-			}
-		}
+	for n := range H.Adjacency[candidateID] {
+		count += setNeighbors[n]
 	}
 	return count
 }
 
 // findMaxDegreeNonAdjacent encuentra el vértice de mayor grado no adyacente al conjunto.
-func findMaxDegreeNonAdjacent(H *graph.ConflictGraph, currentSet []int, merged map[int]bool) int {
+func findMaxDegreeNonAdjacent(H *graph.ConflictGraph, ids []int, merged map[int]bool, setNeighbors map[int]int, rng *rand.Rand) int {
 	bestID := -1
 	maxDegree := -1
+	ties := newTieBreaker(rng)
 
-	for id := range H.Vertices {
+	for _, id := range ids {
 		if merged[id] {
 			continue
 		}
 
 		// Verificar no-adyacencia
-		isAdjacent := false
-		for _, setID := range currentSet {
-			if H.HasEdge(id, setID) {
-				isAdjacent = true
-				break
-			}
-		}
-		if isAdjacent {
+		if setNeighbors[id] > 0 {
 			continue
 		}
 
 		if H.Degree(id) > maxDegree {
 			maxDegree = H.Degree(id)
 			bestID = id
+			ties.reset()
+		} else if H.Degree(id) == maxDegree && ties.take() {
+			bestID = id
 		}
 	}
 	return bestID
 }
 
 // maxDegreeVertex retorna el ID del vértice con mayor grado.
-func maxDegreeVertex(H *graph.ConflictGraph) int {
+func maxDegreeVertex(H *graph.ConflictGraph, rng *rand.Rand) int {
 	maxID := -1
 	maxDeg := -1
-	for id := range H.Vertices {
+	ties := newTieBreaker(rng)
+	for _, id := range H.VertexIDs() {
 		if H.Degree(id) > maxDeg {
 			maxDeg = H.Degree(id)
 			maxID = id
+			ties.reset()
+		} else if H.Degree(id) == maxDeg && ties.take() {
+			maxID = id
 		}
 	}
 	return maxID
 }
 
+// tieBreaker decide empates de forma reproducible al recorrer candidatos en orden de ID.
+// Sin rng se conserva el primero (menor ID); con rng se elige uniformemente entre los empatados.
+type tieBreaker struct {
+	rng  *rand.Rand
+	seen int // candidatos empatados vistos hasta ahora
+}
+
+func newTieBreaker(rng *rand.Rand) *tieBreaker {
+	return &tieBreaker{rng: rng, seen: 1}
+}
+
+// reset se llama cuando aparece un nuevo mejor candidato
+func (t *tieBreaker) reset() {
+	t.seen = 1
+}
+
+// take indica si el nuevo candidato empatado reemplaza al actual
+func (t *tieBreaker) take() bool {
+	t.seen++
+	if t.rng == nil {
+		return false
+	}
+	return t.rng.Intn(t.seen) == 0
+}
+
 // cloneGraph crea una copia del grafo para trabajar sin modificar el original.
 func cloneGraph(g *graph.ConflictGraph) *graph.ConflictGraph {
 	clone := graph.New()
@@ -222,27 +227,67 @@ func removeVertex(H *graph.ConflictGraph, id int) {
 	delete(H.Vertices, id)
 }
 
-// AssignBlocksToColorSets asigna bloques temporales (0-34) a cada ColorSet.
-// Cada color se mapea a un bloque diferente, saltando el bloque protegido del miércoles.
-func AssignBlocksToColorSets(colorSets []ColorSet) {
-	block := 0
+// AssignBlocksToColorSets asigna bloques temporales disjuntos a cada ColorSet, en orden: el primer bloque
+// programable (fuera del horario protegido y de los slots no utilizables) donde todas sus actividades caben en el
+// día, ninguna ocupa un periodo bloqueado para la institución o para sus carreras (según planLocations) y ningún
+// bloque que ocupa su actividad más larga está usado por un ColorSet anterior.
+// Los ColorSets que ya no encuentran bloque quedan sin asignar (Block = -1) y se retornan.
+func AssignBlocksToColorSets(colorSets []ColorSet, planLocations map[string]map[string]int) []ColorSet {
+	courseMajors := buildCourseMajors(planLocations)
+	fits := func(set ColorSet, block int) bool {
+		if !isSchedulableBlock(block) {
+			return false
+		}
+		for _, a := range set.Activities {
+			if !domain.FitsInDay(block, a.Span()) || isBlockedForActivity(a, block, courseMajors) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var unassigned []ColorSet
+	used := make([]bool, domain.TotalBlocks)
+	free := func(block, span int) bool {
+		for b := block; b < block+span && b < domain.TotalBlocks; b++ {
+			if used[b] {
+				return false
+			}
+		}
+		return true
+	}
 	for i := range colorSets {
-		// Saltar el bloque protegido del miércoles (11:30-12:50)
-		for domain.IsProtectedBlock(block) && block < domain.TotalBlocks {
+		span := 1
+		for _, a := range colorSets[i].Activities {
+			if a.Span() > span {
+				span = a.Span()
+			}
+		}
+
+		block := 0
+		for block < domain.TotalBlocks && (!free(block, span) || !fits(colorSets[i], block)) {
 			block++
 		}
 		if block >= domain.TotalBlocks {
-			block = 0
-			// Volver a verificar
-			for domain.IsProtectedBlock(block) && block < domain.TotalBlocks {
-				block++
+			for _, a := range colorSets[i].Activities {
+				a.Block = -1
 			}
+			unassigned = append(unassigned, colorSets[i])
+			continue
 		}
-		for _, activity := range colorSets[i].Activities {
-			activity.Block = block
+		for b := block; b < block+span; b++ {
+			used[b] = true
+		}
+		for _, a := range colorSets[i].Activities {
+			a.Block = block
 		}
-		block++
 	}
+	return unassigned
+}
+
+// isSchedulableBlock verifica si se pueden programar actividades en el bloque
+func isSchedulableBlock(block int) bool {
+	return domain.IsUsableBlock(block) && !domain.IsProtectedBlock(block)
 }
 
 // SortColorSetsBySize ordena los ColorSets por tamaño.
@@ -0,0 +1,240 @@
+package solver
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"timetabling-UDP/internal/graph"
+)
+
+// Nombres de las estrategias de coloreo
+const (
+	ColoringDuttonBrigham = "dutton-brigham"
+	ColoringDSatur        = "dsatur"
+	ColoringRLF           = "rlf"
+	ColoringWelshPowell   = "welsh-powell"
+)
+
+// ColoringStrategy particiona el grafo de conflictos en conjuntos independientes (colores).
+// Color no modifica H; rng decide los empates (si es nil gana el menor ID), así el resultado es reproducible.
+type ColoringStrategy interface {
+	Name() string
+	Color(H *graph.ConflictGraph, rng *rand.Rand) [][]int
+}
+
+// classExtractor lo implementan las estrategias que construyen un color a la vez. El scheduler integrado
+// solo necesita el siguiente conjunto de cada periodo, sin colorear todo el grafo restante.
+type classExtractor interface {
+	nextClass(H *graph.ConflictGraph, rng *rand.Rand) []int
+}
+
+// ColoringStrategies retorna todas las estrategias disponibles, la por defecto primero.
+func ColoringStrategies() []ColoringStrategy {
+	return []ColoringStrategy{DuttonBrigham{}, DSatur{}, RLF{}, WelshPowell{}}
+}
+
+// ColoringStrategyByName retorna la estrategia con ese nombre.
+func ColoringStrategyByName(name string) (ColoringStrategy, error) {
+	var names []string
+	for _, s := range ColoringStrategies() {
+		if s.Name() == name {
+			return s, nil
+		}
+		names = append(names, s.Name())
+	}
+	return nil, fmt.Errorf("estrategia de coloreo desconocida %q (usar %s)", name, strings.Join(names, ", "))
+}
+
+// nextColorClass retorna el conjunto de actividades del siguiente periodo. Para las estrategias que colorean
+// todo el grafo a la vez se usa el color más grande.
+func nextColorClass(s ColoringStrategy, H *graph.ConflictGraph, rng *rand.Rand) []int {
+	if e, ok := s.(classExtractor); ok {
+		return e.nextClass(H, rng)
+	}
+	var largest []int
+	for _, class := range s.Color(H, rng) {
+		if len(class) > len(largest) {
+			largest = class
+		}
+	}
+	return largest
+}
+
+// colorByClasses colorea extrayendo un conjunto independiente tras otro de una copia del grafo.
+func colorByClasses(H *graph.ConflictGraph, rng *rand.Rand, next func(*graph.ConflictGraph, *rand.Rand) []int) [][]int {
+	work := cloneGraph(H)
+	var classes [][]int
+	for work.NumVertices() > 0 {
+		class := next(work, rng)
+		if len(class) == 0 {
+			break
+		}
+		classes = append(classes, class)
+		for _, id := range class {
+			removeVertex(work, id)
+		}
+	}
+	return classes
+}
+
+// DuttonBrigham fusiona en cada color los vértices no adyacentes con más vecinos comunes, partiendo del de mayor grado.
+type DuttonBrigham struct{}
+
+func (DuttonBrigham) Name() string { return ColoringDuttonBrigham }
+
+func (s DuttonBrigham) Color(H *graph.ConflictGraph, rng *rand.Rand) [][]int {
+	return colorByClasses(H, rng, s.nextClass)
+}
+
+func (DuttonBrigham) nextClass(H *graph.ConflictGraph, rng *rand.Rand) []int {
+	return findMaxIndependentSet(H, rng)
+}
+
+// WelshPowell recorre los vértices por grado decreciente y agrega al color cada uno que no sea adyacente a él.
+type WelshPowell struct{}
+
+func (WelshPowell) Name() string { return ColoringWelshPowell }
+
+func (s WelshPowell) Color(H *graph.ConflictGraph, rng *rand.Rand) [][]int {
+	return colorByClasses(H, rng, s.nextClass)
+}
+
+func (WelshPowell) nextClass(H *graph.ConflictGraph, rng *rand.Rand) []int {
+	ids := H.VertexIDs()
+	if rng != nil {
+		rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return H.Degree(ids[i]) > H.Degree(ids[j])
+	})
+
+	var class []int
+	blocked := make(map[int]bool)
+	for _, id := range ids {
+		if blocked[id] {
+			continue
+		}
+		class = append(class, id)
+		for n := range H.Adjacency[id] {
+			blocked[n] = true
+		}
+	}
+	return class
+}
+
+// RLF (Recursive Largest First) parte del vértice de mayor grado y agrega en cada paso el candidato con más vecinos
+// entre los ya excluidos del color; en empate, el de menos vecinos entre los candidatos restantes.
+type RLF struct{}
+
+func (RLF) Name() string { return ColoringRLF }
+
+func (s RLF) Color(H *graph.ConflictGraph, rng *rand.Rand) [][]int {
+	return colorByClasses(H, rng, s.nextClass)
+}
+
+func (RLF) nextClass(H *graph.ConflictGraph, rng *rand.Rand) []int {
+	first := maxDegreeVertex(H, rng)
+	if first == -1 {
+		return nil
+	}
+
+	ids := H.VertexIDs()
+	// Cada vértice es candidato, está en el color o está excluido (adyacente al color)
+	inClass := make(map[int]bool)
+	excluded := make(map[int]bool)
+	toExcluded := make(map[int]int) // vecinos excluidos de cada vértice
+	toCandidates := make(map[int]int)
+	for _, id := range ids {
+		toCandidates[id] = H.Degree(id)
+	}
+
+	var class []int
+	add := func(v int) {
+		class = append(class, v)
+		inClass[v] = true
+		for n := range H.Adjacency[v] {
+			toCandidates[n]--
+		}
+		for n := range H.Adjacency[v] {
+			if excluded[n] {
+				continue
+			}
+			excluded[n] = true
+			for m := range H.Adjacency[n] {
+				toExcluded[m]++
+				toCandidates[m]--
+			}
+		}
+	}
+
+	for v := first; v != -1; {
+		add(v)
+
+		v = -1
+		ties := newTieBreaker(rng)
+		for _, id := range ids {
+			if inClass[id] || excluded[id] {
+				continue
+			}
+			switch {
+			case v == -1, toExcluded[id] > toExcluded[v],
+				toExcluded[id] == toExcluded[v] && toCandidates[id] < toCandidates[v]:
+				v = id
+				ties.reset()
+			case toExcluded[id] == toExcluded[v] && toCandidates[id] == toCandidates[v] && ties.take():
+				v = id
+			}
+		}
+	}
+	return class
+}
+
+// DSatur colorea un vértice a la vez: el de mayor saturación (colores distintos entre sus vecinos), en empate el de
+// mayor grado, con el menor color que no usen sus vecinos.
+type DSatur struct{}
+
+func (DSatur) Name() string { return ColoringDSatur }
+
+func (DSatur) Color(H *graph.ConflictGraph, rng *rand.Rand) [][]int {
+	ids := H.VertexIDs()
+	color := make(map[int]int, len(ids))
+	neighborColors := make(map[int]map[int]bool, len(ids))
+	for _, id := range ids {
+		neighborColors[id] = make(map[int]bool)
+	}
+
+	var classes [][]int
+	for range ids {
+		v := -1
+		ties := newTieBreaker(rng)
+		for _, id := range ids {
+			if _, done := color[id]; done {
+				continue
+			}
+			switch {
+			case v == -1, len(neighborColors[id]) > len(neighborColors[v]),
+				len(neighborColors[id]) == len(neighborColors[v]) && H.Degree(id) > H.Degree(v):
+				v = id
+				ties.reset()
+			case len(neighborColors[id]) == len(neighborColors[v]) && H.Degree(id) == H.Degree(v) && ties.take():
+				v = id
+			}
+		}
+
+		c := 0
+		for neighborColors[v][c] {
+			c++
+		}
+		color[v] = c
+		if c == len(classes) {
+			classes = append(classes, nil)
+		}
+		classes[c] = append(classes[c], v)
+		for n := range H.Adjacency[v] {
+			neighborColors[n][c] = true
+		}
+	}
+	return classes
+}
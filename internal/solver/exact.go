@@ -0,0 +1,626 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// ExactModel es la formulación entera del problema: una variable binaria x(a, b, r) por actividad, bloque de inicio
+// y sala factibles, donde factible ya descarta los bloques que no caben en el día, los periodos bloqueados (incluido
+// el horario protegido), la disponibilidad de los profesores y las salas de otro tipo o sin capacidad. Los conflictos
+// del grafo se expresan como cliques (profesor, sección, semestre) y como aristas sueltas las que ninguna clique cubre.
+// La función objetivo es la misma de SA, sin el término hard porque aquí las restricciones duras se cumplen siempre.
+type ExactModel struct {
+	activities []*domain.Activity
+	rooms      []string       // salas que aparecen en alguna variable, en orden
+	roomIndex  map[string]int // código -> posición en rooms
+	values     [][]exactValue // por actividad, sus variables
+	cliques    []exactClique
+	edges      [][2]int // conflictos no cubiertos por una clique
+	conflict   [][]bool // conflicto entre dos actividades (clique o arista)
+	pairs      []exactPair
+	pairsOf    [][]int // por actividad, índices de sus términos de pares
+	w          *domain.ObjectiveWeights
+}
+
+// exactValue es una variable x(a, b, r) con su costo propio (preferencia de profesor y ayudantía fuera del miércoles)
+type exactValue struct {
+	block int
+	room  string
+	cost  float64
+}
+
+// exactClique es un conjunto de actividades que no pueden coincidir en ningún bloque
+type exactClique struct {
+	name    string
+	members []int
+}
+
+// pairKind es un término de la función objetivo que depende de dos actividades
+type pairKind int
+
+const (
+	pairMirror pairKind = iota // cátedras hermanas en distinto slot
+	pairRoom                   // cátedras hermanas en distinta sala
+	pairDaySep                 // separación de días entre cátedras hermanas
+	pairCATAY                  // cátedra el mismo día que una ayudantía de su grupo
+	pairPrereq                 // curso y prerrequisito en el mismo bloque
+)
+
+type exactPair struct {
+	a, b      int
+	kind      pairKind
+	groupSize int // cátedras del grupo, para la separación de días
+}
+
+// BuildExactModel construye la formulación exacta para las actividades dadas, que deben incluir todas las actividades
+// de cada curso (las cliques de semestre dependen de cuántas secciones tiene el curso)
+func BuildExactModel(activities []domain.Activity, rooms []domain.Room, weights domain.ObjectiveWeights, prerequisites map[string][]string, planLocations map[string]map[string]int, electives map[string]bool, constraints loader.RoomConstraints, teachers []domain.Teacher) *ExactModel {
+	w := weights
+	m := &ExactModel{
+		roomIndex: make(map[string]int),
+		w:         &w,
+	}
+	teacherIndex := buildTeacherIndex(teachers)
+	courseMajors := buildCourseMajors(planLocations)
+	cliqueConflicts := buildCliqueMap(activities, planLocations, electives)
+
+	// La función objetivo de SA aporta los grupos de hermanas y ayudantías, y el costo de los términos de una actividad
+	siblings := buildSiblingIndex(activities)
+	prereqPairs := buildPrereqPairs(prerequisites, buildCourseIndex(activities))
+	obj := newObjective(activities, &w, siblings, prereqPairs, teacherIndex, map[int][]*domain.Activity{}, domain.NewRoomCalendar(), cliqueConflicts, courseMajors)
+
+	index := make(map[int]int, len(activities)) // ID -> posición en el modelo
+	for i := range activities {
+		a := &activities[i]
+		index[a.ID] = len(m.activities)
+		m.activities = append(m.activities, a)
+	}
+
+	// Variables: bloques y salas factibles de cada actividad
+	usedRooms := make(map[string]bool)
+	for _, a := range m.activities {
+		duration := a.Span()
+		var fitting []domain.Room
+		for _, r := range allowedRoomsFor(a, rooms, constraints) {
+			if a.Students <= r.Capacity {
+				fitting = append(fitting, r)
+			}
+		}
+
+		var values []exactValue
+		for b := 0; b < domain.TotalBlocks; b++ {
+			if !domain.FitsInDay(b, duration) || isBlockedForActivity(a, b, courseMajors) || isTeacherBusy(a, b, teacherIndex) {
+				continue
+			}
+			cost := teacherPreferenceCost(a, b, teacherIndex, w.TeacherPreference) + ayWednesdayTerm{}.cost(obj, a, b)
+			for _, r := range fitting {
+				values = append(values, exactValue{block: b, room: r.Code, cost: cost})
+				usedRooms[r.Code] = true
+			}
+		}
+		m.values = append(m.values, values)
+	}
+	m.rooms = sortedSet(usedRooms)
+	for i, code := range m.rooms {
+		m.roomIndex[code] = i
+	}
+
+	// Conflictos: la misma regla que el scheduler y SA
+	n := len(m.activities)
+	m.conflict = make([][]bool, n)
+	for i := range m.conflict {
+		m.conflict[i] = make([]bool, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			a, b := m.activities[i], m.activities[j]
+			if a.SharesTeacher(b) || a.SharesSection(b) || (cliqueConflicts[a.CourseCode] != nil && cliqueConflicts[a.CourseCode][b.CourseCode]) {
+				m.conflict[i][j] = true
+				m.conflict[j][i] = true
+			}
+		}
+	}
+	m.buildCliques(planLocations, cliqueConflicts)
+
+	// Términos de pares de la función objetivo, con los mismos grupos que usa SA
+	for _, groupID := range obj.groupIDs {
+		cats := siblings[groupID]
+		for i := 0; i < len(cats); i++ {
+			for j := i + 1; j < len(cats); j++ {
+				for _, kind := range []pairKind{pairMirror, pairRoom, pairDaySep} {
+					m.pairs = append(m.pairs, exactPair{a: index[cats[i].ID], b: index[cats[j].ID], kind: kind, groupSize: len(cats)})
+				}
+			}
+		}
+		for _, cat := range cats {
+			for _, ay := range obj.groupAYs[groupID] {
+				m.pairs = append(m.pairs, exactPair{a: index[cat.ID], b: index[ay.ID], kind: pairCATAY})
+			}
+		}
+	}
+	for _, pair := range prereqPairs {
+		m.pairs = append(m.pairs, exactPair{a: index[pair.PrereqActivity.ID], b: index[pair.DepActivity.ID], kind: pairPrereq})
+	}
+	m.pairsOf = make([][]int, n)
+	for i, p := range m.pairs {
+		m.pairsOf[p.a] = append(m.pairsOf[p.a], i)
+		m.pairsOf[p.b] = append(m.pairsOf[p.b], i)
+	}
+	return m
+}
+
+// buildCliques agrupa los conflictos en cliques por profesor, sección y semestre; los conflictos que no quedan
+// cubiertos se agregan como aristas
+func (m *ExactModel) buildCliques(planLocations map[string]map[string]int, cliqueConflicts map[string]map[string]bool) {
+	groups := make(map[string][]int)
+	for i, a := range m.activities {
+		for _, t := range a.TeacherNames {
+			groups["profesor "+t] = append(groups["profesor "+t], i)
+		}
+		for _, s := range a.Sections {
+			key := fmt.Sprintf("sección %s-%d", a.CourseCode, s)
+			groups[key] = append(groups[key], i)
+		}
+		if cliqueConflicts[a.CourseCode] == nil {
+			continue
+		}
+		for major, sem := range planLocations[a.CourseCode] {
+			key := fmt.Sprintf("semestre %s-%d", major, sem)
+			groups[key] = append(groups[key], i)
+		}
+	}
+
+	covered := make(map[[2]int]bool)
+	for _, name := range sortedKeysOf(groups) {
+		members := groups[name]
+		if len(members) < 2 || !m.isClique(members) {
+			continue
+		}
+		m.cliques = append(m.cliques, exactClique{name: name, members: members})
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				covered[[2]int{members[i], members[j]}] = true
+			}
+		}
+	}
+
+	for i := range m.activities {
+		for j := i + 1; j < len(m.activities); j++ {
+			if m.conflict[i][j] && !covered[[2]int{i, j}] {
+				m.edges = append(m.edges, [2]int{i, j})
+			}
+		}
+	}
+}
+
+// isClique verifica que todas las actividades del grupo estén en conflicto entre sí
+func (m *ExactModel) isClique(members []int) bool {
+	for i := 0; i < len(members); i++ {
+		for j := i + 1; j < len(members); j++ {
+			if !m.conflict[members[i]][members[j]] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func sortedKeysOf(m map[string][]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NumActivities retorna la cantidad de actividades del modelo
+func (m *ExactModel) NumActivities() int {
+	return len(m.activities)
+}
+
+// NumVars retorna la cantidad de variables x(a, b, r)
+func (m *ExactModel) NumVars() int {
+	total := 0
+	for _, values := range m.values {
+		total += len(values)
+	}
+	return total
+}
+
+// NumCliques retorna la cantidad de cliques de conflicto
+func (m *ExactModel) NumCliques() int {
+	return len(m.cliques)
+}
+
+// NumEdges retorna la cantidad de conflictos que no cubre ninguna clique
+func (m *ExactModel) NumEdges() int {
+	return len(m.edges)
+}
+
+// pairCost retorna el costo del término de pares con las dos actividades en los valores dados
+func (m *ExactModel) pairCost(p exactPair, va, vb exactValue) float64 {
+	dayA, slotA := blockToDaySlot(va.block)
+	dayB, slotB := blockToDaySlot(vb.block)
+	switch p.kind {
+	case pairMirror:
+		if slotA != slotB {
+			return m.w.Mirror
+		}
+	case pairRoom:
+		if va.room != vb.room {
+			return m.w.SiblingRoom
+		}
+	case pairDaySep:
+		if p.groupSize == 2 {
+			return m.w.TwoCATSeparation(abs(dayA - dayB))
+		}
+		return m.w.MultiCATSeparation(abs(dayA - dayB))
+	case pairCATAY:
+		if dayA == dayB {
+			return m.w.CATSameDayAsAY
+		}
+	case pairPrereq:
+		if va.block == vb.block {
+			return m.w.PrereqSameBlock
+		}
+	}
+	return 0
+}
+
+// pairMin retorna una cota inferior del término de pares, para cualquier par de valores
+func (m *ExactModel) pairMin(p exactPair) float64 {
+	var options []float64
+	switch p.kind {
+	case pairMirror:
+		options = []float64{m.w.Mirror}
+	case pairRoom:
+		options = []float64{m.w.SiblingRoom}
+	case pairDaySep:
+		for d := 0; d < domain.DaysPerWeek; d++ {
+			if p.groupSize == 2 {
+				options = append(options, m.w.TwoCATSeparation(d))
+			} else {
+				options = append(options, m.w.MultiCATSeparation(d))
+			}
+		}
+	case pairCATAY:
+		options = []float64{m.w.CATSameDayAsAY}
+	case pairPrereq:
+		options = []float64{m.w.PrereqSameBlock}
+	}
+	low := 0.0
+	for _, v := range options {
+		low = math.Min(low, v)
+	}
+	return low
+}
+
+// clashes indica si dos actividades en los valores dados violan una restricción dura: misma sala o conflicto del grafo
+// en bloques que se traslapan
+func (m *ExactModel) clashes(i int, vi exactValue, j int, vj exactValue) bool {
+	if !overlaps(vi.block, m.activities[i].Span(), vj.block, m.activities[j].Span()) {
+		return false
+	}
+	return vi.room == vj.room || m.conflict[i][j]
+}
+
+// ExactConfig contiene los límites de la búsqueda exacta.
+type ExactConfig struct {
+	NodeLimit int           // Nodos máximos del árbol de búsqueda (0 = sin límite)
+	TimeLimit time.Duration // Tiempo máximo (0 = sin límite)
+}
+
+// DefaultExactConfig retorna límites pensados para instancias de un semestre o un departamento pequeño.
+func DefaultExactConfig() ExactConfig {
+	return ExactConfig{
+		NodeLimit: 2000000,
+		TimeLimit: 60 * time.Second,
+	}
+}
+
+// ExactResult es el resultado de la búsqueda exacta.
+type ExactResult struct {
+	Optimal    bool    // se exploró todo el árbol: Cost es el óptimo
+	Feasible   bool    // se encontró al menos un horario factible (propio o el inicial)
+	Cost       float64 // costo del mejor horario
+	LowerBound float64 // cota inferior del óptimo (igual a Cost si Optimal)
+	StartCost  float64 // costo del horario inicial (NaN si no era factible para el modelo)
+	Nodes      int
+	Elapsed    time.Duration
+}
+
+// Gap retorna la distancia relativa (%) entre un costo y la cota inferior del óptimo
+func (r ExactResult) Gap(cost float64) float64 {
+	return (cost - r.LowerBound) / math.Max(math.Abs(cost), 1) * 100
+}
+
+// SolveExact busca el horario óptimo del modelo con branch-and-bound en profundidad: elige la actividad con menos
+// valores vivos, prueba sus valores de menor a mayor costo y propaga los conflictos a las demás actividades
+// (forward checking). Si las actividades ya tienen un horario factible para el modelo se usa como cota inicial.
+// Al terminar, las actividades quedan con el mejor horario encontrado.
+func SolveExact(m *ExactModel, config ExactConfig) ExactResult {
+	s := newBnB(m, config)
+	start := time.Now()
+
+	result := ExactResult{StartCost: math.NaN()}
+	if initial, ok := m.currentAssignment(); ok {
+		s.best = m.cost(initial)
+		s.bestAssign = initial
+		result.StartCost = s.best
+	}
+
+	if s.search(0) {
+		s.openBound = math.Inf(-1) // cortada antes de calcular la cota de la raíz
+	}
+
+	result.Nodes = s.nodes
+	result.Elapsed = time.Since(start)
+	result.Feasible = s.bestAssign != nil
+	result.Optimal = !s.aborted
+	if result.Feasible {
+		result.Cost = s.best
+		for i, v := range s.bestAssign {
+			m.activities[i].Block = m.values[i][v].block
+			m.activities[i].Room = m.values[i][v].room
+		}
+	}
+	switch {
+	case result.Optimal && result.Feasible:
+		result.LowerBound = s.best
+	case result.Optimal:
+		result.LowerBound = math.Inf(1) // infactible
+	default:
+		result.LowerBound = math.Min(s.best, s.openBound)
+	}
+	return result
+}
+
+// currentAssignment traduce el horario actual de las actividades a valores del modelo, si es factible
+func (m *ExactModel) currentAssignment() ([]int, bool) {
+	assign := make([]int, len(m.activities))
+	for i, a := range m.activities {
+		assign[i] = -1
+		for j, v := range m.values[i] {
+			if v.block == a.Block && v.room == a.Room {
+				assign[i] = j
+				break
+			}
+		}
+		if assign[i] < 0 {
+			return nil, false
+		}
+	}
+	for i := range assign {
+		for j := i + 1; j < len(assign); j++ {
+			if m.clashes(i, m.values[i][assign[i]], j, m.values[j][assign[j]]) {
+				return nil, false
+			}
+		}
+	}
+	return assign, true
+}
+
+// cost evalúa la función objetivo para una asignación completa
+func (m *ExactModel) cost(assign []int) float64 {
+	total := 0.0
+	for i, v := range assign {
+		total += m.values[i][v].cost
+	}
+	for _, p := range m.pairs {
+		total += m.pairCost(p, m.values[p.a][assign[p.a]], m.values[p.b][assign[p.b]])
+	}
+	return total
+}
+
+// bnb es el estado del branch-and-bound
+type bnb struct {
+	m      *ExactModel
+	config ExactConfig
+	start  time.Time
+
+	assign     []int    // valor asignado a cada actividad, -1 si no tiene
+	alive      [][]bool // valores aún compatibles con las actividades asignadas
+	aliveCount []int
+	trail      [][2]int // (actividad, valor) eliminados, para deshacer
+
+	best       float64
+	bestAssign []int
+
+	nodes     int
+	aborted   bool
+	openBound float64 // menor cota de los subárboles que quedaron sin explorar al cortar la búsqueda
+}
+
+func newBnB(m *ExactModel, config ExactConfig) *bnb {
+	n := len(m.activities)
+	s := &bnb{
+		m:          m,
+		config:     config,
+		start:      time.Now(),
+		assign:     make([]int, n),
+		alive:      make([][]bool, n),
+		aliveCount: make([]int, n),
+		best:       math.Inf(1),
+		openBound:  math.Inf(1),
+	}
+	for i := range s.assign {
+		s.assign[i] = -1
+		s.alive[i] = make([]bool, len(m.values[i]))
+		for j := range s.alive[i] {
+			s.alive[i][j] = true
+		}
+		s.aliveCount[i] = len(m.values[i])
+	}
+	return s
+}
+
+// increment retorna el costo de asignar el valor a la actividad, dados los valores de las ya asignadas
+func (s *bnb) increment(i, v int) float64 {
+	m := s.m
+	cost := m.values[i][v].cost
+	for _, pi := range m.pairsOf[i] {
+		p := m.pairs[pi]
+		other := p.b
+		if other == i {
+			other = p.a
+		}
+		if s.assign[other] < 0 {
+			continue
+		}
+		if p.a == i {
+			cost += m.pairCost(p, m.values[i][v], m.values[other][s.assign[other]])
+		} else {
+			cost += m.pairCost(p, m.values[other][s.assign[other]], m.values[i][v])
+		}
+	}
+	return cost
+}
+
+// bound retorna una cota inferior del costo que falta: para cada actividad sin asignar su mejor valor vivo, y para
+// cada par con ambas actividades sin asignar el menor costo posible del término. Retorna también la menor
+// contribución de cada actividad, y false si alguna se quedó sin valores.
+func (s *bnb) bound() (float64, []float64, bool) {
+	m := s.m
+	total := 0.0
+	mins := make([]float64, len(m.activities))
+	for i := range m.activities {
+		if s.assign[i] >= 0 {
+			continue
+		}
+		best := math.Inf(1)
+		for v, ok := range s.alive[i] {
+			if ok {
+				best = math.Min(best, s.increment(i, v))
+			}
+		}
+		if math.IsInf(best, 1) {
+			return 0, nil, false
+		}
+		mins[i] = best
+		total += best
+	}
+	for _, p := range m.pairs {
+		if s.assign[p.a] < 0 && s.assign[p.b] < 0 {
+			total += m.pairMin(p)
+		}
+	}
+	return total, mins, true
+}
+
+// search explora el subárbol con costo acumulado g. Retorna true si se cortó por límite antes de explorarlo,
+// para que quien lo llamó registre su cota como pendiente.
+func (s *bnb) search(g float64) bool {
+	s.nodes++
+	if s.limitReached() {
+		s.aborted = true
+		return true
+	}
+
+	// Elegir la actividad sin asignar con menos valores vivos
+	next := -1
+	for i := range s.m.activities {
+		if s.assign[i] < 0 && (next < 0 || s.aliveCount[i] < s.aliveCount[next]) {
+			next = i
+		}
+	}
+	if next < 0 {
+		if g < s.best {
+			s.best = g
+			s.bestAssign = append([]int(nil), s.assign...)
+		}
+		return false
+	}
+
+	rest, mins, ok := s.bound()
+	if !ok {
+		return false
+	}
+	nodeBound := g + rest
+	if nodeBound >= s.best-1e-9 {
+		return false
+	}
+
+	// Valores de menor a mayor costo incremental
+	type candidate struct {
+		value int
+		inc   float64
+	}
+	var candidates []candidate
+	for v, ok := range s.alive[next] {
+		if ok {
+			candidates = append(candidates, candidate{v, s.increment(next, v)})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].inc < candidates[j].inc })
+
+	for _, c := range candidates {
+		// Cota de asignar este valor: la del nodo cambiando el mejor valor de la actividad por este
+		childBound := nodeBound - mins[next] + c.inc
+		if childBound >= s.best-1e-9 {
+			break
+		}
+		if s.aborted {
+			s.openBound = math.Min(s.openBound, childBound)
+			return false
+		}
+
+		mark := len(s.trail)
+		s.assign[next] = c.value
+		if s.propagate(next, c.value) && s.search(g+c.inc) {
+			s.openBound = math.Min(s.openBound, childBound)
+		}
+		s.undo(mark)
+		s.assign[next] = -1
+	}
+	return false
+}
+
+// propagate elimina los valores de las actividades sin asignar que chocan con el valor asignado; retorna false si
+// alguna se queda sin valores
+func (s *bnb) propagate(i, v int) bool {
+	m := s.m
+	vi := m.values[i][v]
+	for j := range m.activities {
+		if s.assign[j] >= 0 {
+			continue
+		}
+		for k, ok := range s.alive[j] {
+			if ok && m.clashes(i, vi, j, m.values[j][k]) {
+				s.alive[j][k] = false
+				s.aliveCount[j]--
+				s.trail = append(s.trail, [2]int{j, k})
+			}
+		}
+		if s.aliveCount[j] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// undo restaura los valores eliminados desde la marca dada
+func (s *bnb) undo(mark int) {
+	for len(s.trail) > mark {
+		e := s.trail[len(s.trail)-1]
+		s.trail = s.trail[:len(s.trail)-1]
+		s.alive[e[0]][e[1]] = true
+		s.aliveCount[e[0]]++
+	}
+}
+
+func (s *bnb) limitReached() bool {
+	if s.aborted {
+		return true
+	}
+	if s.config.NodeLimit > 0 && s.nodes > s.config.NodeLimit {
+		return true
+	}
+	return s.config.TimeLimit > 0 && s.nodes%256 == 0 && time.Since(s.start) > s.config.TimeLimit
+}
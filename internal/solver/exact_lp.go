@@ -0,0 +1,391 @@
+package solver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// lpTerm es un coeficiente por una variable
+type lpTerm struct {
+	coef float64
+	name string
+}
+
+// lpRow es una restricción lineal: terms sense rhs
+type lpRow struct {
+	name  string
+	terms []lpTerm
+	sense string // "<=", ">=" o "="
+	rhs   float64
+}
+
+// lpProblem es el modelo lineal completo, independiente del formato en que se escriba
+type lpProblem struct {
+	comments  []string
+	objective []lpTerm
+	rows      []lpRow
+	binaries  []string
+}
+
+// lpOne es una variable fija en 1, para las constantes del objetivo y para marcar actividades sin variables
+const lpOne = "one"
+
+func (m *ExactModel) varName(i, v int) string {
+	val := m.values[i][v]
+	return fmt.Sprintf("x_a%d_b%d_r%d", i, val.block, m.roomIndex[val.room])
+}
+
+// linearize arma el modelo lineal. Los términos de pares se linealizan con una variable z por valor compartido del
+// atributo que comparan (slot, sala, día o bloque): z = 1 exactamente cuando ambas actividades toman ese valor.
+func (m *ExactModel) linearize() lpProblem {
+	var lp lpProblem
+
+	lp.comments = append(lp.comments, "Horario UDP: x_a<actividad>_b<bloque>_r<sala> = 1 si la actividad comienza en el bloque y sala")
+	for i, a := range m.activities {
+		lp.comments = append(lp.comments, fmt.Sprintf("a%d = %s", i, a.Code))
+	}
+	for k, code := range m.rooms {
+		lp.comments = append(lp.comments, fmt.Sprintf("r%d = %s", k, code))
+	}
+	for k, c := range m.cliques {
+		lp.comments = append(lp.comments, fmt.Sprintf("clique%d = %s", k, c.name))
+	}
+
+	// Costo propio de cada variable
+	for i := range m.activities {
+		for v, val := range m.values[i] {
+			if val.cost != 0 {
+				lp.objective = append(lp.objective, lpTerm{val.cost, m.varName(i, v)})
+			}
+			lp.binaries = append(lp.binaries, m.varName(i, v))
+		}
+	}
+
+	// Cada actividad en exactamente un bloque y sala
+	for i := range m.activities {
+		row := lpRow{name: fmt.Sprintf("asignar_a%d", i), sense: "=", rhs: 1}
+		for v := range m.values[i] {
+			row.terms = append(row.terms, lpTerm{1, m.varName(i, v)})
+		}
+		if len(row.terms) == 0 {
+			row.terms = []lpTerm{{0, lpOne}} // sin bloques ni salas factibles: el modelo es infactible
+		}
+		lp.rows = append(lp.rows, row)
+	}
+
+	// Variables que ocupan cada bloque, por actividad y por sala
+	T := domain.TotalBlocks
+	byActivity := make([][][]lpTerm, len(m.activities)) // actividad -> bloque -> variables
+	byRoom := make([][][]lpTerm, len(m.rooms))          // sala -> bloque -> variables
+	for k := range m.rooms {
+		byRoom[k] = make([][]lpTerm, T)
+	}
+	for i, a := range m.activities {
+		byActivity[i] = make([][]lpTerm, T)
+		for v, val := range m.values[i] {
+			term := lpTerm{1, m.varName(i, v)}
+			for t := val.block; t < val.block+a.Span() && t < T; t++ {
+				byActivity[i][t] = append(byActivity[i][t], term)
+				byRoom[m.roomIndex[val.room]][t] = append(byRoom[m.roomIndex[val.room]][t], term)
+			}
+		}
+	}
+	atMostOne := func(name string, members []int, t int) {
+		row := lpRow{name: name, sense: "<=", rhs: 1}
+		contributing := 0
+		for _, i := range members {
+			if terms := byActivity[i][t]; len(terms) > 0 {
+				contributing++
+				row.terms = append(row.terms, terms...)
+			}
+		}
+		if contributing > 1 {
+			lp.rows = append(lp.rows, row)
+		}
+	}
+
+	// Una actividad por sala y bloque
+	for k := range m.rooms {
+		for t := 0; t < T; t++ {
+			if len(byRoom[k][t]) > 1 {
+				lp.rows = append(lp.rows, lpRow{name: fmt.Sprintf("sala_r%d_t%d", k, t), terms: byRoom[k][t], sense: "<=", rhs: 1})
+			}
+		}
+	}
+
+	// Conflictos del grafo: cliques y aristas sueltas
+	for k, c := range m.cliques {
+		for t := 0; t < T; t++ {
+			atMostOne(fmt.Sprintf("clique%d_t%d", k, t), c.members, t)
+		}
+	}
+	for _, e := range m.edges {
+		for t := 0; t < T; t++ {
+			atMostOne(fmt.Sprintf("conflicto_a%d_a%d_t%d", e[0], e[1], t), []int{e[0], e[1]}, t)
+		}
+	}
+
+	// Términos de pares
+	for pi, p := range m.pairs {
+		m.linearizePair(&lp, pi, p)
+	}
+
+	lp.objective = mergeTerms(lp.objective)
+	return lp
+}
+
+// linearizePair agrega las variables z, sus restricciones y su costo para un término de pares
+func (m *ExactModel) linearizePair(lp *lpProblem, pi int, p exactPair) {
+	// Atributo que compara el término, y costo según si ambas actividades coinciden en él
+	attr := func(val exactValue) string {
+		day, slot := blockToDaySlot(val.block)
+		switch p.kind {
+		case pairMirror:
+			return strconv.Itoa(slot)
+		case pairRoom:
+			return strconv.Itoa(m.roomIndex[val.room])
+		case pairDaySep, pairCATAY:
+			return strconv.Itoa(day)
+		default:
+			return strconv.Itoa(val.block)
+		}
+	}
+	indicator := func(i int) map[string][]lpTerm {
+		terms := make(map[string][]lpTerm)
+		for v, val := range m.values[i] {
+			k := attr(val)
+			terms[k] = append(terms[k], lpTerm{1, m.varName(i, v)})
+		}
+		return terms
+	}
+	ia, ib := indicator(p.a), indicator(p.b)
+
+	// z(ka, kb) = A(ka) * B(kb), con su costo en el objetivo
+	product := func(ka, kb string, cost float64) {
+		z := fmt.Sprintf("z_p%d_%s_%s", pi, ka, kb)
+		lp.binaries = append(lp.binaries, z)
+		lp.objective = append(lp.objective, lpTerm{cost, z})
+		lp.rows = append(lp.rows,
+			lpRow{name: z + "_a", terms: append([]lpTerm{{1, z}}, negate(ia[ka])...), sense: "<=", rhs: 0},
+			lpRow{name: z + "_b", terms: append([]lpTerm{{1, z}}, negate(ib[kb])...), sense: "<=", rhs: 0},
+			lpRow{name: z + "_ab", terms: append(append([]lpTerm{{-1, z}}, ia[ka]...), ib[kb]...), sense: "<=", rhs: 1},
+		)
+	}
+
+	switch p.kind {
+	case pairDaySep:
+		for _, ka := range sortedAttrKeys(ia) {
+			for _, kb := range sortedAttrKeys(ib) {
+				da, _ := strconv.Atoi(ka)
+				db, _ := strconv.Atoi(kb)
+				var cost float64
+				if p.groupSize == 2 {
+					cost = m.w.TwoCATSeparation(abs(da - db))
+				} else {
+					cost = m.w.MultiCATSeparation(abs(da - db))
+				}
+				if cost != 0 {
+					product(ka, kb, cost)
+				}
+			}
+		}
+	case pairMirror, pairRoom:
+		// Cuesta w salvo que coincidan: w - w * sum z
+		w := m.w.Mirror
+		if p.kind == pairRoom {
+			w = m.w.SiblingRoom
+		}
+		if w == 0 {
+			return
+		}
+		lp.objective = append(lp.objective, lpTerm{w, lpOne})
+		for _, k := range sortedAttrKeys(ia) {
+			if len(ib[k]) > 0 {
+				product(k, k, -w)
+			}
+		}
+	default:
+		// Cuesta w si coinciden
+		w := m.w.CATSameDayAsAY
+		if p.kind == pairPrereq {
+			w = m.w.PrereqSameBlock
+		}
+		if w == 0 {
+			return
+		}
+		for _, k := range sortedAttrKeys(ia) {
+			if len(ib[k]) > 0 {
+				product(k, k, w)
+			}
+		}
+	}
+}
+
+func negate(terms []lpTerm) []lpTerm {
+	out := make([]lpTerm, len(terms))
+	for i, t := range terms {
+		out[i] = lpTerm{-t.coef, t.name}
+	}
+	return out
+}
+
+func sortedAttrKeys(m map[string][]lpTerm) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+	return keys
+}
+
+// mergeTerms suma los coeficientes de una misma variable, conservando el orden de aparición
+func mergeTerms(terms []lpTerm) []lpTerm {
+	index := make(map[string]int)
+	var out []lpTerm
+	for _, t := range terms {
+		if i, ok := index[t.name]; ok {
+			out[i].coef += t.coef
+			continue
+		}
+		index[t.name] = len(out)
+		out = append(out, t)
+	}
+	return out
+}
+
+// WriteLP escribe el modelo en formato LP (CPLEX), que leen CPLEX, Gurobi, HiGHS, CBC, SCIP y GLPK
+func (m *ExactModel) WriteLP(w io.Writer) error {
+	lp := m.linearize()
+	bw := bufio.NewWriter(w)
+
+	for _, c := range lp.comments {
+		fmt.Fprintf(bw, "\\ %s\n", c)
+	}
+	fmt.Fprintln(bw, "Minimize")
+	fmt.Fprint(bw, " obj:")
+	writeLPTerms(bw, lp.objective)
+	fmt.Fprintln(bw)
+
+	fmt.Fprintln(bw, "Subject To")
+	for _, row := range lp.rows {
+		fmt.Fprintf(bw, " %s:", row.name)
+		writeLPTerms(bw, row.terms)
+		fmt.Fprintf(bw, " %s %s\n", row.sense, formatCoef(row.rhs))
+	}
+
+	fmt.Fprintln(bw, "Bounds")
+	fmt.Fprintf(bw, " %s = 1\n", lpOne)
+
+	fmt.Fprintln(bw, "Binaries")
+	for i := 0; i < len(lp.binaries); i += 8 {
+		end := i + 8
+		if end > len(lp.binaries) {
+			end = len(lp.binaries)
+		}
+		fmt.Fprintf(bw, " %s\n", strings.Join(lp.binaries[i:end], " "))
+	}
+	fmt.Fprintln(bw, "End")
+	return bw.Flush()
+}
+
+// writeLPTerms escribe una suma de términos, partiendo la línea cada 8 términos
+func writeLPTerms(w io.Writer, terms []lpTerm) {
+	if len(terms) == 0 {
+		fmt.Fprintf(w, " 0 %s", lpOne)
+		return
+	}
+	for i, t := range terms {
+		if i > 0 && i%8 == 0 {
+			fmt.Fprint(w, "\n   ")
+		}
+		sign := "+"
+		coef := t.coef
+		if coef < 0 {
+			sign = "-"
+			coef = -coef
+		}
+		fmt.Fprintf(w, " %s %s %s", sign, formatCoef(coef), t.name)
+	}
+}
+
+func formatCoef(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteMPS escribe el modelo en formato MPS libre
+func (m *ExactModel) WriteMPS(w io.Writer) error {
+	lp := m.linearize()
+	bw := bufio.NewWriter(w)
+
+	for _, c := range lp.comments {
+		fmt.Fprintf(bw, "* %s\n", c)
+	}
+	fmt.Fprintln(bw, "NAME timetabling")
+
+	fmt.Fprintln(bw, "ROWS")
+	fmt.Fprintln(bw, " N obj")
+	senses := map[string]string{"<=": "L", ">=": "G", "=": "E"}
+	for _, row := range lp.rows {
+		fmt.Fprintf(bw, " %s %s\n", senses[row.sense], row.name)
+	}
+
+	// Columnas: cada variable con sus coeficientes en el objetivo y en las filas
+	type entry struct {
+		row  string
+		coef float64
+	}
+	columns := make(map[string][]entry)
+	var order []string
+	add := func(name, row string, coef float64) {
+		if _, ok := columns[name]; !ok {
+			order = append(order, name)
+		}
+		columns[name] = append(columns[name], entry{row, coef})
+	}
+	add(lpOne, "obj", 0)
+	for _, t := range lp.objective {
+		add(t.name, "obj", t.coef)
+	}
+	for _, row := range lp.rows {
+		for _, t := range mergeTerms(row.terms) {
+			add(t.name, row.name, t.coef)
+		}
+	}
+	for _, name := range lp.binaries {
+		if _, ok := columns[name]; !ok {
+			add(name, "obj", 0)
+		}
+	}
+
+	fmt.Fprintln(bw, "COLUMNS")
+	for _, name := range order {
+		for _, e := range columns[name] {
+			fmt.Fprintf(bw, " %s %s %s\n", name, e.row, formatCoef(e.coef))
+		}
+	}
+
+	fmt.Fprintln(bw, "RHS")
+	for _, row := range lp.rows {
+		if row.rhs != 0 {
+			fmt.Fprintf(bw, " RHS %s %s\n", row.name, formatCoef(row.rhs))
+		}
+	}
+
+	fmt.Fprintln(bw, "BOUNDS")
+	fmt.Fprintf(bw, " FX BND %s 1\n", lpOne)
+	for _, name := range lp.binaries {
+		fmt.Fprintf(bw, " BV BND %s\n", name)
+	}
+	fmt.Fprintln(bw, "ENDATA")
+	return bw.Flush()
+}
@@ -0,0 +1,127 @@
+package solver
+
+import (
+	"math"
+	"testing"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// TestSolveExactMatchesBruteForce compara el óptimo del branch-and-bound con la enumeración de todos los horarios
+// de una instancia diminuta, evaluados con la función objetivo de SA
+func TestSolveExactMatchesBruteForce(t *testing.T) {
+	domain.SetTimeGrid(domain.TimeGrid{
+		Days:  []domain.GridDay{{Name: "Martes"}, {Name: "Miércoles"}},
+		Slots: []domain.TimeSlot{{Start: "08:30", End: "09:50"}, {Start: "10:00", End: "11:20"}, {Start: "11:30", End: "12:50"}},
+		Blocked: []domain.BlockedPeriod{
+			{Name: "Consejo de carrera", Day: 1, From: 0, To: 0, Majors: []string{"EIT"}},
+		},
+	})
+	t.Cleanup(func() { domain.SetTimeGrid(domain.DefaultTimeGrid()) })
+
+	group := loader.SiblingGroupID("CIT1000", []int{1})
+	activities := []domain.Activity{
+		domain.NewActivity(0, "CIT1000-CATEDRA1-S1", "CIT1000", "Cálculo", domain.CAT, 1, []int{1}, 30, []string{"Ana"}, group, 1),
+		domain.NewActivity(1, "CIT1000-CATEDRA1-S2", "CIT1000", "Cálculo", domain.CAT, 1, []int{1}, 30, []string{"Ana"}, group, 1),
+		domain.NewActivity(2, "CIT1000-AYUDANTIA1-S1", "CIT1000", "Cálculo", domain.AY, 1, []int{1}, 30, []string{"Diego"}, "", 1),
+		domain.NewActivity(3, "CIT1010-CATEDRA1-S1", "CIT1010", "Física", domain.CAT, 1, []int{1}, 15, []string{"Bruno"}, loader.SiblingGroupID("CIT1010", []int{1}), 1),
+		domain.NewActivity(4, "CIT2000-LABORATORIO1-S1", "CIT2000", "Programación", domain.LAB, 1, []int{1}, 20, []string{"Ana"}, "", 2),
+	}
+	rooms := []domain.Room{
+		{ID: 0, Code: "101", Capacity: 40, Type: domain.RoomClassroom},
+		{ID: 1, Code: "102", Capacity: 20, Type: domain.RoomClassroom},
+		{ID: 2, Code: "LAB A", Capacity: 25, Type: domain.RoomLab},
+	}
+	teachers := []domain.Teacher{
+		{ID: 0, Name: "Ana", Availability: domain.Availability{
+			Preferences: map[int][]domain.SlotPreference{
+				0: {{SlotRange: domain.SlotRange{From: 0, To: 0}, Level: domain.Preferred}},
+				1: {{SlotRange: domain.SlotRange{From: 2, To: 2}, Level: domain.StronglyUndesired}},
+			},
+		}},
+		{ID: 1, Name: "Bruno", Availability: domain.Availability{
+			Unavailable: map[int][]domain.SlotRange{0: {{From: 2, To: 2}}},
+		}},
+		{ID: 2, Name: "Diego"},
+	}
+	planLocations := map[string]map[string]int{
+		"CIT1000": {"EIT": 1},
+		"CIT1010": {"EIT": 1},
+		"CIT2000": {"EIT": 2},
+	}
+	prerequisites := map[string][]string{"CIT2000": {"CIT1000"}}
+	weights := domain.DefaultObjectiveWeights()
+
+	best := bruteForceOptimum(activities, rooms, &weights, prerequisites, planLocations, teachers)
+	if math.IsInf(best, 1) {
+		t.Fatalf("la instancia no tiene horarios factibles")
+	}
+
+	model := BuildExactModel(activities, rooms, weights, prerequisites, planLocations, nil, nil, teachers)
+	result := SolveExact(model, ExactConfig{})
+	if !result.Optimal || !result.Feasible {
+		t.Fatalf("SolveExact no terminó con un óptimo (optimal=%v, feasible=%v)", result.Optimal, result.Feasible)
+	}
+	if math.Abs(result.Cost-best) > 1e-6 {
+		t.Fatalf("SolveExact retornó costo %.4f, la enumeración encontró %.4f", result.Cost, best)
+	}
+
+	// El horario que deja SolveExact en las actividades debe valer lo mismo con la función objetivo de SA
+	obj := scheduleObjective(activities, &weights, prerequisites, planLocations, teachers)
+	if obj.hard != 0 {
+		t.Fatalf("el horario óptimo tiene %d violaciones duras", obj.hard)
+	}
+	if cost := obj.total(); math.Abs(cost-result.Cost) > 1e-6 {
+		t.Fatalf("el horario óptimo cuesta %.4f con la función objetivo de SA, SolveExact reportó %.4f", cost, result.Cost)
+	}
+}
+
+// bruteForceOptimum enumera todas las combinaciones de bloque y sala y retorna el menor costo de un horario sin
+// violaciones duras (+Inf si no hay ninguno)
+func bruteForceOptimum(activities []domain.Activity, rooms []domain.Room, w *domain.ObjectiveWeights, prerequisites map[string][]string, planLocations map[string]map[string]int, teachers []domain.Teacher) float64 {
+	best := math.Inf(1)
+	var enumerate func(i int)
+	enumerate = func(i int) {
+		if i == len(activities) {
+			obj := scheduleObjective(activities, w, prerequisites, planLocations, teachers)
+			if obj.hard == 0 {
+				best = math.Min(best, obj.total())
+			}
+			return
+		}
+		a := &activities[i]
+		for b := 0; b < domain.TotalBlocks; b++ {
+			if !domain.FitsInDay(b, a.Duration) {
+				continue
+			}
+			for _, r := range allowedRoomsFor(a, rooms, nil) {
+				if a.Students > r.Capacity || roomTaken(activities[:i], b, a.Span(), r.Code) {
+					continue
+				}
+				a.Block, a.Room = b, r.Code
+				enumerate(i + 1)
+			}
+		}
+		a.Block, a.Room = -1, ""
+	}
+	enumerate(0)
+	return best
+}
+
+// roomTaken indica si alguna de las actividades ya ubicadas usa la sala en bloques que se traslapan
+func roomTaken(placed []domain.Activity, block, duration int, room string) bool {
+	for i := range placed {
+		if placed[i].Room == room && overlaps(placed[i].Block, placed[i].Span(), block, duration) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleObjective construye la función objetivo de SA sobre el horario actual de las actividades
+func scheduleObjective(activities []domain.Activity, w *domain.ObjectiveWeights, prerequisites map[string][]string, planLocations map[string]map[string]int, teachers []domain.Teacher) *objective {
+	return newObjective(activities, w, buildSiblingIndex(activities), buildPrereqPairs(prerequisites, buildCourseIndex(activities)),
+		buildTeacherIndex(teachers), buildBlockOccupancy(activities), domain.BuildRoomCalendar(activities),
+		buildCliqueMap(activities, planLocations, nil), buildCourseMajors(planLocations))
+}
@@ -0,0 +1,255 @@
+package solver
+
+import (
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/graph"
+	"timetabling-UDP/internal/loader"
+)
+
+// DUDReason clasifica por qué una actividad no pudo programarse
+type DUDReason string
+
+const (
+	ReasonNoRoom             DUDReason = "SIN_SALA"               // ninguna sala del tipo requerido o permitida por rooms_constraints
+	ReasonCapacity           DUDReason = "CAPACIDAD"              // ninguna sala permitida tiene capacidad suficiente
+	ReasonRoomsBusy          DUDReason = "SALAS_OCUPADAS"         // las salas permitidas que sirven están ocupadas
+	ReasonBlockedPeriod      DUDReason = "PERIODO_BLOQUEADO"      // periodo bloqueado para la institución o su carrera
+	ReasonTeacherUnavailable DUDReason = "PROFESOR_NO_DISPONIBLE" // fuera de la disponibilidad del profesor
+	ReasonTeacherBusy        DUDReason = "PROFESOR_OCUPADO"       // el profesor ya dicta otra actividad en el bloque
+	ReasonSection            DUDReason = "SECCION"                // la misma sección ya tiene otra actividad en el bloque
+	ReasonSemester           DUDReason = "SEMESTRE"               // clique de semestre: otro curso del mismo semestre
+	ReasonDegree             DUDReason = "GRADO"                  // más actividades en conflicto que bloques disponibles
+	ReasonOrdering           DUDReason = "ORDEN_GREEDY"           // hay bloques factibles, el greedy no llegó a usarlos
+	ReasonOutsideDay         DUDReason = "FUERA_DEL_DIA"          // el bloque publicado ya no cabe en el día (re-optimización)
+)
+
+// blockReasons es el orden en que se atribuye la causa de descartar un bloque, de la más a la menos rígida
+var blockReasons = []DUDReason{
+	ReasonBlockedPeriod,
+	ReasonTeacherUnavailable,
+	ReasonTeacherBusy,
+	ReasonSection,
+	ReasonSemester,
+	ReasonRoomsBusy,
+}
+
+// DUDReport explica por qué una actividad quedó sin programar
+type DUDReport struct {
+	Activity *domain.Activity
+
+	AllowedRooms int // salas permitidas por tipo o rooms_constraints
+	FittingRooms int // salas permitidas con capacidad suficiente
+	MaxCapacity  int // mayor capacidad entre las salas permitidas
+
+	Degree          int // actividades en conflicto en el grafo
+	CandidateBlocks int // bloques de inicio donde la actividad cabe en la grilla
+	FreeBlocks      int // bloques donde hoy se podría ubicar (sin conflicto y con sala libre)
+
+	BlockCauses map[DUDReason]int // bloques descartados por cada causa
+
+	UnavailableTeachers []string // profesores cuya disponibilidad descarta algún bloque
+	Conflicts           []string // actividades programadas que ocupan los bloques (códigos)
+
+	Reasons    []DUDReason // causas principales, de mayor a menor peso
+	Suggestion string      // acción recomendada para el planificador
+}
+
+// ExplainUnscheduled diagnostica cada actividad DUD contra el estado final del scheduler. G debe ser el grafo
+// de conflictos completo (el scheduler vacía el que recibe), y activities las actividades con sus bloques y salas.
+func ExplainUnscheduled(dud []*domain.Activity, activities []domain.Activity, G *graph.ConflictGraph, rooms []domain.Room, constraints loader.RoomConstraints, teachers []domain.Teacher, planLocations map[string]map[string]int) []DUDReport {
+	teacherIndex := buildTeacherIndex(teachers)
+	courseMajors := buildCourseMajors(planLocations)
+
+	// Ocupación de salas por bloque de las actividades programadas
+	roomCal := domain.BuildRoomCalendar(activities)
+
+	reports := make([]DUDReport, 0, len(dud))
+	for _, a := range dud {
+		reports = append(reports, explainActivity(a, G, rooms, constraints, teacherIndex, courseMajors, roomCal))
+	}
+	return reports
+}
+
+func explainActivity(a *domain.Activity, G *graph.ConflictGraph, rooms []domain.Room, constraints loader.RoomConstraints, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string, roomCal *domain.RoomCalendar) DUDReport {
+	r := DUDReport{
+		Activity:    a,
+		Degree:      G.Degree(a.ID),
+		BlockCauses: make(map[DUDReason]int),
+	}
+	duration := a.Span()
+
+	// Salas permitidas con el mismo criterio del scheduler
+	var fitting []string
+	for _, room := range allowedRoomsFor(a, rooms, constraints) {
+		r.AllowedRooms++
+		if room.Capacity > r.MaxCapacity {
+			r.MaxCapacity = room.Capacity
+		}
+		if a.Students <= room.Capacity {
+			fitting = append(fitting, room.Code)
+		}
+	}
+	r.FittingRooms = len(fitting)
+
+	if r.AllowedRooms == 0 {
+		r.Reasons = []DUDReason{ReasonNoRoom}
+		r.Suggestion = "agregar salas permitidas en rooms_constraints.json o salas del tipo requerido"
+		return r
+	}
+	if r.FittingRooms == 0 {
+		r.Reasons = []DUDReason{ReasonCapacity}
+		r.Suggestion = "abrir una nueva sección o permitir salas más grandes"
+		return r
+	}
+
+	// Vecinos ya programados, con la causa del conflicto
+	type neighbor struct {
+		activity *domain.Activity
+		reason   DUDReason
+	}
+	var scheduled []neighbor
+	for _, id := range G.Neighbors(a.ID) {
+		n := G.Vertices[id]
+		if n.Block < 0 {
+			continue
+		}
+		reason := ReasonSemester
+		if a.SharesTeacher(n) {
+			reason = ReasonTeacherBusy
+		} else if a.SharesSection(n) {
+			reason = ReasonSection
+		}
+		scheduled = append(scheduled, neighbor{n, reason})
+	}
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].activity.ID < scheduled[j].activity.ID })
+
+	unavailable := make(map[string]bool)
+	conflicts := make(map[string]bool)
+
+	for b := 0; b < domain.TotalBlocks; b++ {
+		if !domain.FitsInDay(b, duration) {
+			continue
+		}
+		r.CandidateBlocks++
+
+		causes := make(map[DUDReason]bool)
+		if isBlockedForActivity(a, b, courseMajors) {
+			causes[ReasonBlockedPeriod] = true
+		}
+		for _, name := range a.TeacherNames {
+			if t, ok := teacherIndex[name]; ok && !t.IsAvailable(b, duration) {
+				causes[ReasonTeacherUnavailable] = true
+				unavailable[name] = true
+			}
+		}
+		for _, n := range scheduled {
+			if overlaps(b, duration, n.activity.Block, n.activity.Span()) {
+				causes[n.reason] = true
+				conflicts[n.activity.Code] = true
+			}
+		}
+		roomFree := false
+		for _, code := range fitting {
+			if roomCal.IsFree(a, code, b) {
+				roomFree = true
+				break
+			}
+		}
+		if !roomFree {
+			causes[ReasonRoomsBusy] = true
+		}
+
+		// Se atribuye el bloque a la causa más rígida
+		attributed := false
+		for _, reason := range blockReasons {
+			if causes[reason] {
+				r.BlockCauses[reason]++
+				attributed = true
+				break
+			}
+		}
+		if !attributed {
+			r.FreeBlocks++
+		}
+	}
+
+	r.UnavailableTeachers = sortedSet(unavailable)
+	r.Conflicts = sortedSet(conflicts)
+
+	// Causas principales ordenadas por cantidad de bloques descartados
+	for _, reason := range blockReasons {
+		if r.BlockCauses[reason] > 0 {
+			r.Reasons = append(r.Reasons, reason)
+		}
+	}
+	sort.SliceStable(r.Reasons, func(i, j int) bool {
+		return r.BlockCauses[r.Reasons[i]] > r.BlockCauses[r.Reasons[j]]
+	})
+	if r.Degree >= r.CandidateBlocks {
+		r.Reasons = append(r.Reasons, ReasonDegree)
+	}
+	if r.FreeBlocks > 0 {
+		r.Reasons = append([]DUDReason{ReasonOrdering}, r.Reasons...)
+	}
+
+	r.Suggestion = suggestionFor(r)
+	return r
+}
+
+// suggestionFor elige la acción recomendada según la causa principal
+func suggestionFor(r DUDReport) string {
+	if len(r.Reasons) == 0 {
+		return ""
+	}
+	switch r.Reasons[0] {
+	case ReasonOrdering:
+		return "hay bloques libres: reintentar con otra semilla o reparar el horario"
+	case ReasonRoomsBusy:
+		return "ampliar la lista de salas permitidas o liberar salas en los bloques factibles"
+	case ReasonBlockedPeriod:
+		return "revisar los periodos bloqueados de la carrera en time_grid.json"
+	case ReasonTeacherUnavailable:
+		return "ampliar la disponibilidad del profesor o asignar otro profesor"
+	case ReasonTeacherBusy:
+		return "el profesor tiene demasiada carga: mover alguna de sus actividades o asignar otro profesor"
+	case ReasonSection:
+		return "la sección tiene demasiadas actividades: revisar la distribución del curso"
+	case ReasonSemester:
+		return "el semestre está saturado: abrir otra sección o mover un curso del semestre"
+	case ReasonDegree:
+		return "dividir la actividad o abrir una nueva sección para reducir conflictos"
+	}
+	return ""
+}
+
+// allowedRoomsFor retorna las salas donde puede ir la actividad: las de rooms_constraints, o las de su tipo
+func allowedRoomsFor(a *domain.Activity, rooms []domain.Room, constraints loader.RoomConstraints) []domain.Room {
+	allowedCodes := constraints.GetAllowedRooms(a.CourseCode, eventTypeToString(a.Type))
+	var allowed []domain.Room
+	for _, room := range rooms {
+		if allowedCodes != nil {
+			if contains(allowedCodes, room.Code) {
+				allowed = append(allowed, room)
+			}
+		} else if (a.Type == domain.LAB) == (room.Type == domain.RoomLab) {
+			allowed = append(allowed, room)
+		}
+	}
+	return allowed
+}
+
+// overlaps verifica si dos intervalos de bloques [b1, b1+d1) y [b2, b2+d2) se traslapan
+func overlaps(b1, d1, b2, d2 int) bool {
+	return b1 < b2+d2 && b2 < b1+d1
+}
+
+func sortedSet(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for k := range set {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return list
+}
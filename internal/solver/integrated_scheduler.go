@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"math/rand"
 	"sort"
 
 	"timetabling-UDP/internal/domain"
@@ -24,47 +25,86 @@ type TimetableResult struct {
 }
 
 // IntegratedSchedulerWithConstraints implementa el Algoritmo Integrado con restricciones de salas.
-// Recibe el grafo ya construido
-func IntegratedSchedulerWithConstraints(activities []domain.Activity, G *graph.ConflictGraph, rooms []domain.Room, constraints loader.RoomConstraints) TimetableResult {
+// Recibe el grafo ya construido. Las actividades cuyos profesores no están disponibles en el bloque actual,
+// o cuya carrera tiene el bloque bloqueado, se postergan. Una actividad de varios bloques solo comienza donde
+// termina el mismo día, y reserva su sala y bloquea a sus vecinos del grafo en todos los bloques que ocupa.
+// strategy elige el conjunto de actividades de cada periodo (Dutton-Brigham si es nil).
+// rng decide los empates del coloreo; si es nil se usa el menor ID, por lo que el resultado es siempre el mismo.
+func IntegratedSchedulerWithConstraints(activities []domain.Activity, G *graph.ConflictGraph, rooms []domain.Room, constraints loader.RoomConstraints, teachers []domain.Teacher, planLocations map[string]map[string]int, strategy ColoringStrategy, rng *rand.Rand) TimetableResult {
+	if strategy == nil {
+		strategy = DuttonBrigham{}
+	}
+
 	// Separar salas por tipo
 	classrooms := GetRoomsByType(rooms, domain.RoomClassroom)
 	labs := GetRoomsByType(rooms, domain.RoomLab)
 	allRooms := append(classrooms, labs...)
 
+	// Índice de profesores para validar disponibilidad
+	teacherIndex := buildTeacherIndex(teachers)
+
+	// Carreras de cada curso para los periodos bloqueados por carrera
+	courseMajors := buildCourseMajors(planLocations)
+
 	// El grafo G ya viene construido desde main
 
+	// Salas ocupadas por bloque, y para cada actividad pendiente el primer bloque en que ya no choca con un
+	// vecino programado que sigue en clases
+	roomCal := domain.NewRoomCalendar()
+	busyUntil := make(map[int]int)
+
 	var periods []Period
 	periodNum := 0
-	blockNum := 0 // Bloque temporal real (0-34), puede saltar el protegido
+	blockNum := 0 // Bloque temporal real, puede saltar el protegido y slots no utilizables
 
 	// Mientras queden vértices en el grafo
 	for G.NumVertices() > 0 && blockNum < domain.TotalBlocks {
-		// Saltar el bloque protegido del miércoles
-		if domain.IsProtectedBlock(blockNum) {
+		// Saltar el bloque protegido del miércoles y slots fuera de la grilla
+		if !isSchedulableBlock(blockNum) {
 			blockNum++
 			continue
 		}
 
-		colorSet := findMaxIndependentSet(G)
+		// El conjunto del periodo se arma sobre el subgrafo de las actividades que pueden comenzar en este bloque
+		// (caben en el día, no chocan con un vecino de varios bloques, sin profesores ocupados ni carreras
+		// bloqueadas), así es maximal entre ellas: una actividad que solo choca con otras que no pueden comenzar
+		// aquí también entra
+		startable := cloneGraph(G)
+		for _, id := range G.VertexIDs() {
+			a := G.Vertices[id]
+			if !domain.FitsInDay(blockNum, a.Duration) || busyUntil[id] > blockNum ||
+				isTeacherBusy(a, blockNum, teacherIndex) || isBlockedForActivity(a, blockNum, courseMajors) {
+				removeVertex(startable, id)
+			}
+		}
+		if startable.NumVertices() == 0 {
+			blockNum++
+			continue
+		}
 
+		colorSet := nextColorClass(strategy, startable, rng)
 		if len(colorSet) == 0 {
 			break
 		}
-
-		// Obtener actividades del colorSet
-		var periodActivities []*domain.Activity
-		for _, id := range colorSet {
-			periodActivities = append(periodActivities, G.Vertices[id])
+		periodActivities := make([]*domain.Activity, len(colorSet))
+		for i, id := range colorSet {
+			periodActivities[i] = G.Vertices[id]
 		}
 
 		// Asignar salas usando Algoritmo 2 CON restricciones
-		period := assignRoomsToPeriodWithConstraints(periodActivities, allRooms, constraints, blockNum)
+		period := assignRoomsToPeriodWithConstraints(periodActivities, allRooms, constraints, blockNum, roomCal)
 
 		periods = append(periods, period)
 
-		// Eliminar vértices asignados exitosamente
+		// Eliminar vértices asignados exitosamente, sus vecinos no pueden comenzar hasta que terminen
 		for _, ra := range period.Assignments {
 			for _, a := range ra.Activities {
+				end := blockNum + a.Span()
+				for n := range G.Adjacency[a.ID] {
+					if busyUntil[n] < end {
+						busyUntil[n] = end
+					}
+				}
 				removeVertex(G, a.ID)
 				a.Block = blockNum // Usar bloque real, no periodNum
 			}
@@ -74,10 +114,10 @@ func IntegratedSchedulerWithConstraints(activities []domain.Activity, G *graph.C
 		blockNum++
 	}
 
-	// DUD final
+	// DUD final, en orden de ID
 	var finalDUD []*domain.Activity
-	for _, a := range G.Vertices {
-		finalDUD = append(finalDUD, a)
+	for _, id := range G.VertexIDs() {
+		finalDUD = append(finalDUD, G.Vertices[id])
 	}
 
 	return TimetableResult{
@@ -90,20 +130,15 @@ func IntegratedSchedulerWithConstraints(activities []domain.Activity, G *graph.C
 // IntegratedScheduler versión sin restricciones.
 func IntegratedScheduler(activities []domain.Activity, rooms []domain.Room) TimetableResult {
 	G := graph.BuildFromActivities(activities)
-	return IntegratedSchedulerWithConstraints(activities, G, rooms, nil)
+	return IntegratedSchedulerWithConstraints(activities, G, rooms, nil, nil, nil, nil, nil)
 }
 
 // assignRoomsToPeriodWithConstraints asigna salas respetando restricciones.
-func assignRoomsToPeriodWithConstraints(activities []*domain.Activity, rooms []domain.Room, constraints loader.RoomConstraints, periodNum int) Period {
+// roomCal tiene las salas ocupadas por bloque; cada sala asignada se reserva en todos los bloques que ocupa la actividad.
+func assignRoomsToPeriodWithConstraints(activities []*domain.Activity, rooms []domain.Room, constraints loader.RoomConstraints, periodNum int, roomCal *domain.RoomCalendar) Period {
 	var allAssignments []RoomAssignment
 	var allDUD []*domain.Activity
 
-	// Agrupar actividades por restricción de sala
-	roomAvailability := make(map[string]bool) // Salas disponibles en este periodo
-	for _, r := range rooms {
-		roomAvailability[r.Code] = true
-	}
-
 	// Procesar cada actividad individualmente respetando su restricción
 	for _, activity := range activities {
 		// Obtener salas permitidas para esta actividad
@@ -113,8 +148,8 @@ func assignRoomsToPeriodWithConstraints(activities []*domain.Activity, rooms []d
 		// Filtrar salas permitidas que estén disponibles
 		var availableRooms []domain.Room
 		for _, r := range rooms {
-			if !roomAvailability[r.Code] {
-				continue // Ya usada en este periodo
+			if !roomCal.IsFree(activity, r.Code, periodNum) {
+				continue // Ya usada en alguno de los bloques que ocuparía
 			}
 
 			// Si hay restricción explícita, usar solo esas salas
@@ -148,7 +183,7 @@ func assignRoomsToPeriodWithConstraints(activities []*domain.Activity, rooms []d
 		for _, room := range availableRooms {
 			if activity.Students <= room.Capacity {
 				activity.Room = room.Code
-				roomAvailability[room.Code] = false // Marcar como usada
+				roomCal.Reserve(activity, room.Code, periodNum) // Marcar como usada
 				allAssignments = append(allAssignments, RoomAssignment{
 					RoomCode:   room.Code,
 					Capacity:   room.Capacity,
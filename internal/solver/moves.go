@@ -0,0 +1,304 @@
+package solver
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// Tipos de movimiento de SA
+const (
+	MoveBlock   = "block"   // mover una actividad a otro bloque
+	MoveRoom    = "room"    // cambiar la sala de una actividad
+	MoveSwap    = "swap"    // intercambiar bloque y sala de dos actividades
+	MoveSibling = "sibling" // mover juntas las cátedras de un grupo de hermanas, manteniendo el espejo
+	MoveKempe   = "kempe"   // intercambiar entre dos bloques una cadena de Kempe del grafo de conflictos
+)
+
+// MoveNames es el orden en que se listan los tipos de movimiento
+var MoveNames = []string{MoveBlock, MoveRoom, MoveSwap, MoveSibling, MoveKempe}
+
+// MoveProbabilities indica con qué probabilidad relativa se elige cada tipo de movimiento
+type MoveProbabilities map[string]float64
+
+// DefaultMoveProbabilities retorna la mezcla de movimientos por defecto
+func DefaultMoveProbabilities() MoveProbabilities {
+	return MoveProbabilities{
+		MoveBlock:   0.35,
+		MoveRoom:    0.35,
+		MoveSwap:    0.15,
+		MoveSibling: 0.10,
+		MoveKempe:   0.05,
+	}
+}
+
+// ParseMoveProbabilities lee probabilidades con el formato "block=0.4,room=0.4,swap=0.2"; los tipos omitidos quedan en 0
+func ParseMoveProbabilities(s string) (MoveProbabilities, error) {
+	probs := MoveProbabilities{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("movimiento %q sin probabilidad (usar nombre=valor)", part)
+		}
+		name = strings.TrimSpace(name)
+		if !isMoveName(name) {
+			return nil, fmt.Errorf("movimiento desconocido %q (usar %s)", name, strings.Join(MoveNames, ", "))
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("probabilidad inválida %q para %s", value, name)
+		}
+		probs[name] = p
+	}
+	if probs.total() <= 0 {
+		return nil, fmt.Errorf("al menos un movimiento debe tener probabilidad positiva")
+	}
+	return probs, nil
+}
+
+func isMoveName(name string) bool {
+	for _, n := range MoveNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p MoveProbabilities) total() float64 {
+	total := 0.0
+	for _, v := range p {
+		total += v
+	}
+	return total
+}
+
+// pick elige un tipo de movimiento según las probabilidades (normalizadas)
+func (p MoveProbabilities) pick(rng *rand.Rand) string {
+	r := rng.Float64() * p.total()
+	last := MoveBlock
+	for _, name := range MoveNames {
+		if p[name] <= 0 {
+			continue
+		}
+		last = name
+		if r < p[name] {
+			return name
+		}
+		r -= p[name]
+	}
+	return last
+}
+
+// MoveStats cuenta cuántas veces se intentó, fue factible, se aceptó y mejoró cada tipo de movimiento
+type MoveStats struct {
+	Attempted int
+	Feasible  int
+	Accepted  int
+	Improving int
+}
+
+// placement es el destino de una actividad dentro de un movimiento compuesto.
+// Si la sala no sirve o está ocupada en el destino se busca otra sala válida.
+type placement struct {
+	activity *domain.Activity
+	block    int
+	room     string
+}
+
+// neighborhood genera y aplica los movimientos compuestos de SA sobre el estado de la función objetivo
+type neighborhood struct {
+	obj         *objective
+	rooms       []domain.Room
+	roomMap     map[string]domain.Room
+	constraints loader.RoomConstraints
+	rng         *rand.Rand
+
+	byKind map[bool][]*domain.Activity // actividades por tipo de sala (true = laboratorio), para los swaps
+}
+
+func newNeighborhood(obj *objective, rooms []domain.Room, roomMap map[string]domain.Room, constraints loader.RoomConstraints, rng *rand.Rand) *neighborhood {
+	n := &neighborhood{
+		obj:         obj,
+		rooms:       rooms,
+		roomMap:     roomMap,
+		constraints: constraints,
+		rng:         rng,
+		byKind:      make(map[bool][]*domain.Activity),
+	}
+	for i := range obj.activities {
+		a := &obj.activities[i]
+		n.byKind[a.Type == domain.LAB] = append(n.byKind[a.Type == domain.LAB], a)
+	}
+	return n
+}
+
+// swapMove intercambia bloque y sala de la actividad con otra del mismo tipo de sala y duración
+func (n *neighborhood) swapMove(a *domain.Activity) []placement {
+	candidates := n.byKind[a.Type == domain.LAB]
+	b := candidates[n.rng.Intn(len(candidates))]
+	if b.ID == a.ID || b.Block < 0 || b.Block == a.Block || b.Span() != a.Span() {
+		return nil
+	}
+	return []placement{
+		{activity: a, block: b.Block, room: b.Room},
+		{activity: b, block: a.Block, room: a.Room},
+	}
+}
+
+// siblingMove mueve todas las cátedras del grupo de la actividad a un mismo slot nuevo, cada una en su día
+func (n *neighborhood) siblingMove(a *domain.Activity) []placement {
+	cats := n.obj.catSiblings(a)
+	if len(cats) < 2 {
+		return nil
+	}
+	slot := n.rng.Intn(domain.BlocksPerDay)
+
+	var moves []placement
+	changed := false
+	for _, cat := range cats {
+		if cat.Block < 0 {
+			return nil
+		}
+		day, oldSlot := blockToDaySlot(cat.Block)
+		if oldSlot != slot {
+			changed = true
+		}
+		moves = append(moves, placement{activity: cat, block: day*domain.BlocksPerDay + slot, room: cat.Room})
+	}
+	if !changed {
+		return nil
+	}
+	return moves
+}
+
+// kempeMove intercambia entre el bloque de la actividad y otro bloque la cadena de Kempe que la contiene:
+// la componente conexa, en el grafo de conflictos, de las actividades que comienzan en alguno de los dos bloques
+func (n *neighborhood) kempeMove(a *domain.Activity) []placement {
+	b1 := a.Block
+	b2 := n.rng.Intn(domain.TotalBlocks)
+	if b2 == b1 || !isSchedulableBlock(b2) {
+		return nil
+	}
+
+	var pool []*domain.Activity
+	for _, b := range []int{b1, b2} {
+		for _, x := range n.obj.blockOcc[b] {
+			if x.Block == b {
+				pool = append(pool, x)
+			}
+		}
+	}
+
+	inChain := map[int]bool{a.ID: true}
+	chain := []*domain.Activity{a}
+	for i := 0; i < len(chain); i++ {
+		for _, x := range pool {
+			if !inChain[x.ID] && x.Block != chain[i].Block && n.conflicting(chain[i], x) {
+				inChain[x.ID] = true
+				chain = append(chain, x)
+			}
+		}
+	}
+	sort.Slice(chain, func(i, j int) bool { return chain[i].ID < chain[j].ID })
+
+	moves := make([]placement, 0, len(chain))
+	for _, x := range chain {
+		target := b2
+		if x.Block == b2 {
+			target = b1
+		}
+		moves = append(moves, placement{activity: x, block: target, room: x.Room})
+	}
+	return moves
+}
+
+// conflicting indica si dos actividades no pueden compartir bloque (arista del grafo de conflictos)
+func (n *neighborhood) conflicting(a, b *domain.Activity) bool {
+	if a.SharesTeacher(b) || a.SharesSection(b) {
+		return true
+	}
+	return n.obj.cliqueConflicts[a.CourseCode] != nil && n.obj.cliqueConflicts[a.CourseCode][b.CourseCode]
+}
+
+// tryPlacements aplica el movimiento compuesto si deja el horario factible y retorna el delta de costo y las
+// posiciones anteriores para deshacerlo. Solo se usa con el horario sin violaciones duras.
+func (n *neighborhood) tryPlacements(moves []placement) (float64, []placement, bool) {
+	o := n.obj
+	if o.hard > 0 || len(moves) == 0 {
+		return 0, nil, false
+	}
+
+	undo := make([]placement, len(moves))
+	for i, m := range moves {
+		undo[i] = placement{activity: m.activity, block: m.activity.Block, room: m.activity.Room}
+	}
+
+	// Sacar todas las actividades del movimiento de los índices antes de ubicarlas
+	for _, m := range moves {
+		removeFromOccupancy(m.activity, m.activity.Block, m.activity.Room, o.blockOcc, o.roomCal)
+	}
+
+	// Ubicarlas una a una: la suma de los deltas sucesivos es el delta del movimiento completo
+	delta := 0.0
+	placed := 0
+	for _, m := range moves {
+		a := m.activity
+		room := m.room
+		if room == "" || !n.roomAllowed(a, room) || isRoomBusy(a, m.block, room, o.roomCal) {
+			room = selectValidRoom(a, m.block, n.rooms, n.roomMap, n.constraints, o.roomCal, n.rng)
+		}
+		if room == "" || hasConflictInBlockWithRoom(a, m.block, room, o.blockOcc, o.roomCal, o.cliqueConflicts, o.teacherIndex, o.courseMajors) {
+			break
+		}
+		d, _ := o.moveDelta(a, m.block, room)
+		delta += d
+		a.Block, a.Room = m.block, room
+		addToOccupancy(a, m.block, room, o.blockOcc, o.roomCal)
+		placed++
+	}
+
+	if placed < len(moves) {
+		n.restore(moves[:placed], undo)
+		return 0, nil, false
+	}
+	return delta, undo, true
+}
+
+// undoPlacements deshace un movimiento compuesto ya aplicado
+func (n *neighborhood) undoPlacements(undo []placement) {
+	n.restore(undo, undo)
+}
+
+// restore saca de los índices las actividades ya ubicadas y vuelve todas a sus posiciones anteriores
+func (n *neighborhood) restore(placed []placement, undo []placement) {
+	o := n.obj
+	for _, m := range placed {
+		removeFromOccupancy(m.activity, m.activity.Block, m.activity.Room, o.blockOcc, o.roomCal)
+	}
+	for _, u := range undo {
+		u.activity.Block, u.activity.Room = u.block, u.room
+		addToOccupancy(u.activity, u.block, u.room, o.blockOcc, o.roomCal)
+	}
+}
+
+// roomAllowed verifica tipo, restricción y capacidad de la sala para la actividad (RC4, RC5 y RC6)
+func (n *neighborhood) roomAllowed(a *domain.Activity, code string) bool {
+	room, ok := n.roomMap[code]
+	if !ok || a.Students > room.Capacity {
+		return false
+	}
+	if allowed := n.constraints.GetAllowedRooms(a.CourseCode, eventTypeToString(a.Type)); allowed != nil {
+		return contains(allowed, code)
+	}
+	return (a.Type == domain.LAB) == (room.Type == domain.RoomLab)
+}
@@ -0,0 +1,403 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+)
+
+// costTerm es un término de la función objetivo. full evalúa el horario completo y delta el cambio de costo
+// si la actividad pasa a (block, room) con el resto del horario fijo; ambos usan la misma definición del término.
+type costTerm interface {
+	name() string
+	full(o *objective) float64
+	delta(o *objective, a *domain.Activity, block int, room string) float64
+}
+
+// objective es la función objetivo de SA: la suma de los términos blandos más las violaciones duras.
+// Es la única implementación del costo, tanto para el total como para los deltas de cada movimiento.
+type objective struct {
+	w     *domain.ObjectiveWeights
+	terms []costTerm
+
+	activities   []domain.Activity
+	siblings     map[string][]*domain.Activity // SiblingGroupID -> cátedras hermanas
+	groupIDs     []string                      // grupos ordenados, para sumar siempre en el mismo orden
+	groupAYs     map[string][]*domain.Activity // SiblingGroupID -> ayudantías de las mismas secciones
+	ayGroups     map[int][]string              // ID de ayudantía -> grupos de cátedras a los que pertenece
+	prereqPairs  []PrereqPair
+	prereqsOf    map[int][]int // ID de actividad -> índices de sus pares de prerrequisito
+	teacherIndex map[string]*domain.Teacher
+
+	// Estado para las violaciones duras
+	blockOcc        map[int][]*domain.Activity
+	roomCal         *domain.RoomCalendar
+	cliqueConflicts map[string]map[string]bool
+	courseMajors    map[string][]string
+	hard            int // violaciones duras actuales
+
+	anchors map[int]Anchor // ID de actividad -> bloque y sala publicados (solo al re-optimizar)
+}
+
+// newObjective construye la función objetivo sobre las actividades y los índices de ocupación de SA
+func newObjective(activities []domain.Activity, w *domain.ObjectiveWeights, siblings map[string][]*domain.Activity, prereqPairs []PrereqPair, teacherIndex map[string]*domain.Teacher, blockOcc map[int][]*domain.Activity, roomCal *domain.RoomCalendar, cliqueConflicts map[string]map[string]bool, courseMajors map[string][]string) *objective {
+	o := &objective{
+		w:               w,
+		activities:      activities,
+		siblings:        siblings,
+		groupAYs:        make(map[string][]*domain.Activity),
+		ayGroups:        make(map[int][]string),
+		prereqPairs:     prereqPairs,
+		prereqsOf:       make(map[int][]int),
+		teacherIndex:    teacherIndex,
+		blockOcc:        blockOcc,
+		roomCal:         roomCal,
+		cliqueConflicts: cliqueConflicts,
+		courseMajors:    courseMajors,
+	}
+	o.terms = []costTerm{
+		teacherPreferenceTerm{},
+		ayWednesdayTerm{},
+		mirrorTerm{},
+		siblingRoomTerm{},
+		daySeparationTerm{},
+		catSameDayAsAYTerm{},
+		prereqTerm{},
+	}
+
+	for groupID := range siblings {
+		o.groupIDs = append(o.groupIDs, groupID)
+	}
+	sort.Strings(o.groupIDs)
+
+	// Ayudantías de cada grupo de cátedras: mismo curso y alguna sección en común
+	for i := range activities {
+		ay := &activities[i]
+		if ay.Type != domain.AY {
+			continue
+		}
+		for _, groupID := range o.groupIDs {
+			cats := siblings[groupID]
+			if len(cats) > 0 && cats[0].CourseCode == ay.CourseCode && cats[0].SharesSection(ay) {
+				o.groupAYs[groupID] = append(o.groupAYs[groupID], ay)
+				o.ayGroups[ay.ID] = append(o.ayGroups[ay.ID], groupID)
+			}
+		}
+	}
+
+	for i, pair := range prereqPairs {
+		o.prereqsOf[pair.PrereqActivity.ID] = append(o.prereqsOf[pair.PrereqActivity.ID], i)
+		o.prereqsOf[pair.DepActivity.ID] = append(o.prereqsOf[pair.DepActivity.ID], i)
+	}
+
+	o.hard = countTotalHardViolations(activities, blockOcc, cliqueConflicts, teacherIndex, courseMajors)
+	return o
+}
+
+// total recalcula el costo completo del horario
+func (o *objective) total() float64 {
+	cost := o.w.Hard * float64(countTotalHardViolations(o.activities, o.blockOcc, o.cliqueConflicts, o.teacherIndex, o.courseMajors))
+	for _, t := range o.terms {
+		cost += t.full(o)
+	}
+	return cost
+}
+
+// breakdown retorna el costo de cada término, incluyendo las violaciones duras
+func (o *objective) breakdown() map[string]float64 {
+	terms := map[string]float64{
+		"hard": o.w.Hard * float64(countTotalHardViolations(o.activities, o.blockOcc, o.cliqueConflicts, o.teacherIndex, o.courseMajors)),
+	}
+	for _, t := range o.terms {
+		terms[t.name()] = t.full(o)
+	}
+	return terms
+}
+
+// moveDelta retorna el cambio de costo y de violaciones duras si la actividad pasa a (block, room).
+// Con el horario factible, SA rechaza antes los movimientos que generan conflictos, así que no se cuentan.
+func (o *objective) moveDelta(a *domain.Activity, block int, room string) (float64, int) {
+	hardDelta := 0
+	if o.hard > 0 {
+		hardDelta = countHardViolations(a, block, room, o.blockOcc, o.cliqueConflicts, o.teacherIndex, o.courseMajors) -
+			countHardViolations(a, a.Block, a.Room, o.blockOcc, o.cliqueConflicts, o.teacherIndex, o.courseMajors)
+	}
+
+	delta := o.w.Hard * float64(hardDelta)
+	for _, t := range o.terms {
+		delta += t.delta(o, a, block, room)
+	}
+	return delta, hardDelta
+}
+
+// apply mueve la actividad a (block, room) actualizando los índices de ocupación y el conteo de violaciones
+func (o *objective) apply(a *domain.Activity, block int, room string, hardDelta int) {
+	removeFromOccupancy(a, a.Block, a.Room, o.blockOcc, o.roomCal)
+	a.Block = block
+	a.Room = room
+	addToOccupancy(a, block, room, o.blockOcc, o.roomCal)
+	o.hard += hardDelta
+}
+
+// check compara el costo acumulado por los deltas con el recálculo completo, y falla si no coinciden
+func (o *objective) check(currentCost float64, iteration int) {
+	full := o.total()
+	if math.Abs(full-currentCost) > 1e-6*math.Max(1, math.Abs(full)) {
+		panic(fmt.Sprintf("costo incremental %.4f distinto del recálculo %.4f en la iteración %d (términos: %v)", currentCost, full, iteration, o.breakdown()))
+	}
+	hard := countTotalHardViolations(o.activities, o.blockOcc, o.cliqueConflicts, o.teacherIndex, o.courseMajors)
+	if hard != o.hard {
+		panic(fmt.Sprintf("violaciones duras acumuladas %d distintas del recálculo %d en la iteración %d", o.hard, hard, iteration))
+	}
+}
+
+// withMove evalúa eval antes y después de mover temporalmente la actividad, y retorna la diferencia
+func withMove(a *domain.Activity, block int, room string, eval func() float64) float64 {
+	before := eval()
+	oldBlock, oldRoom := a.Block, a.Room
+	a.Block, a.Room = block, room
+	after := eval()
+	a.Block, a.Room = oldBlock, oldRoom
+	return after - before
+}
+
+// catSiblings retorna las cátedras del grupo de la actividad, nil si no pertenece a uno
+func (o *objective) catSiblings(a *domain.Activity) []*domain.Activity {
+	if a.SiblingGroupID == "" || a.Type != domain.CAT {
+		return nil
+	}
+	return o.siblings[a.SiblingGroupID]
+}
+
+// sumGroups suma el costo de cada grupo de cátedras con la función dada
+func (o *objective) sumGroups(cost func(cats []*domain.Activity) float64) float64 {
+	total := 0.0
+	for _, groupID := range o.groupIDs {
+		total += cost(o.siblings[groupID])
+	}
+	return total
+}
+
+// pairCost suma el costo de cada par de cátedras de un grupo
+func pairCost(cats []*domain.Activity, cost func(a, b *domain.Activity) float64) float64 {
+	total := 0.0
+	for i := 0; i < len(cats); i++ {
+		for j := i + 1; j < len(cats); j++ {
+			total += cost(cats[i], cats[j])
+		}
+	}
+	return total
+}
+
+// teacherPreferenceTerm: bloques en rangos no deseados (o deseados) por los profesores
+type teacherPreferenceTerm struct{}
+
+func (teacherPreferenceTerm) name() string { return "teacher_preference" }
+
+func (teacherPreferenceTerm) full(o *objective) float64 {
+	cost := 0.0
+	for i := range o.activities {
+		a := &o.activities[i]
+		cost += teacherPreferenceCost(a, a.Block, o.teacherIndex, o.w.TeacherPreference)
+	}
+	return cost
+}
+
+func (teacherPreferenceTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	return teacherPreferenceCost(a, block, o.teacherIndex, o.w.TeacherPreference) -
+		teacherPreferenceCost(a, a.Block, o.teacherIndex, o.w.TeacherPreference)
+}
+
+// ayWednesdayTerm: ayudantías fuera del miércoles
+type ayWednesdayTerm struct{}
+
+func (ayWednesdayTerm) name() string { return "ay_not_wednesday" }
+
+func (t ayWednesdayTerm) cost(o *objective, a *domain.Activity, block int) float64 {
+	if a.Type != domain.AY {
+		return 0
+	}
+	if day, _ := blockToDaySlot(block); day != domain.WednesdayDay {
+		return o.w.AYNotWednesday
+	}
+	return 0
+}
+
+func (t ayWednesdayTerm) full(o *objective) float64 {
+	cost := 0.0
+	for i := range o.activities {
+		cost += t.cost(o, &o.activities[i], o.activities[i].Block)
+	}
+	return cost
+}
+
+func (t ayWednesdayTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	return t.cost(o, a, block) - t.cost(o, a, a.Block)
+}
+
+// mirrorTerm: pares de cátedras hermanas en distinto slot
+type mirrorTerm struct{}
+
+func (mirrorTerm) name() string { return "mirror" }
+
+func (mirrorTerm) cost(o *objective, cats []*domain.Activity) float64 {
+	return pairCost(cats, func(a, b *domain.Activity) float64 {
+		_, slotA := blockToDaySlot(a.Block)
+		_, slotB := blockToDaySlot(b.Block)
+		if slotA != slotB {
+			return o.w.Mirror
+		}
+		return 0
+	})
+}
+
+func (t mirrorTerm) full(o *objective) float64 {
+	return o.sumGroups(func(cats []*domain.Activity) float64 { return t.cost(o, cats) })
+}
+
+func (t mirrorTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	cats := o.catSiblings(a)
+	if len(cats) < 2 {
+		return 0
+	}
+	return withMove(a, block, room, func() float64 { return t.cost(o, cats) })
+}
+
+// siblingRoomTerm: pares de cátedras hermanas en distinta sala
+type siblingRoomTerm struct{}
+
+func (siblingRoomTerm) name() string { return "sibling_room" }
+
+func (siblingRoomTerm) cost(o *objective, cats []*domain.Activity) float64 {
+	return pairCost(cats, func(a, b *domain.Activity) float64 {
+		if a.Room != b.Room {
+			return o.w.SiblingRoom
+		}
+		return 0
+	})
+}
+
+func (t siblingRoomTerm) full(o *objective) float64 {
+	return o.sumGroups(func(cats []*domain.Activity) float64 { return t.cost(o, cats) })
+}
+
+func (t siblingRoomTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	cats := o.catSiblings(a)
+	if len(cats) < 2 {
+		return 0
+	}
+	return withMove(a, block, room, func() float64 { return t.cost(o, cats) })
+}
+
+// daySeparationTerm: separación de días entre cátedras hermanas
+type daySeparationTerm struct{}
+
+func (daySeparationTerm) name() string { return "day_separation" }
+
+func (daySeparationTerm) cost(o *objective, cats []*domain.Activity) float64 {
+	return pairCost(cats, func(a, b *domain.Activity) float64 {
+		dayA, _ := blockToDaySlot(a.Block)
+		dayB, _ := blockToDaySlot(b.Block)
+		if len(cats) == 2 {
+			return o.w.TwoCATSeparation(abs(dayA - dayB))
+		}
+		return o.w.MultiCATSeparation(abs(dayA - dayB))
+	})
+}
+
+func (t daySeparationTerm) full(o *objective) float64 {
+	return o.sumGroups(func(cats []*domain.Activity) float64 { return t.cost(o, cats) })
+}
+
+func (t daySeparationTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	cats := o.catSiblings(a)
+	if len(cats) < 2 {
+		return 0
+	}
+	return withMove(a, block, room, func() float64 { return t.cost(o, cats) })
+}
+
+// catSameDayAsAYTerm: cátedras el mismo día que una ayudantía de sus secciones
+type catSameDayAsAYTerm struct{}
+
+func (catSameDayAsAYTerm) name() string { return "cat_same_day_as_ay" }
+
+func (catSameDayAsAYTerm) cost(o *objective, groupID string) float64 {
+	cost := 0.0
+	for _, cat := range o.siblings[groupID] {
+		catDay, _ := blockToDaySlot(cat.Block)
+		for _, ay := range o.groupAYs[groupID] {
+			if ayDay, _ := blockToDaySlot(ay.Block); ayDay == catDay {
+				cost += o.w.CATSameDayAsAY
+			}
+		}
+	}
+	return cost
+}
+
+func (t catSameDayAsAYTerm) full(o *objective) float64 {
+	cost := 0.0
+	for _, groupID := range o.groupIDs {
+		cost += t.cost(o, groupID)
+	}
+	return cost
+}
+
+func (t catSameDayAsAYTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	// Una cátedra afecta a su grupo, una ayudantía a todos los grupos de sus secciones
+	var groups []string
+	switch {
+	case a.Type == domain.CAT && a.SiblingGroupID != "":
+		if len(o.groupAYs[a.SiblingGroupID]) > 0 {
+			groups = []string{a.SiblingGroupID}
+		}
+	case a.Type == domain.AY:
+		groups = o.ayGroups[a.ID]
+	}
+	if len(groups) == 0 {
+		return 0
+	}
+	return withMove(a, block, room, func() float64 {
+		cost := 0.0
+		for _, g := range groups {
+			cost += t.cost(o, g)
+		}
+		return cost
+	})
+}
+
+// prereqTerm: curso y prerrequisito en el mismo bloque (normalmente negativo, es un bono)
+type prereqTerm struct{}
+
+func (prereqTerm) name() string { return "prereq_same_block" }
+
+func (prereqTerm) cost(o *objective, pair PrereqPair) float64 {
+	if pair.PrereqActivity.Block == pair.DepActivity.Block {
+		return o.w.PrereqSameBlock
+	}
+	return 0
+}
+
+func (t prereqTerm) full(o *objective) float64 {
+	cost := 0.0
+	for _, pair := range o.prereqPairs {
+		cost += t.cost(o, pair)
+	}
+	return cost
+}
+
+func (t prereqTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	pairs := o.prereqsOf[a.ID]
+	if len(pairs) == 0 {
+		return 0
+	}
+	return withMove(a, block, room, func() float64 {
+		cost := 0.0
+		for _, i := range pairs {
+			cost += t.cost(o, o.prereqPairs[i])
+		}
+		return cost
+	})
+}
@@ -0,0 +1,184 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// testInstance arma una instancia pequeña que activa todos los términos de la función objetivo: cátedras hermanas
+// de 2 y 3 sesiones, ayudantías, un laboratorio de 2 bloques, profesores compartidos con preferencias e
+// indisponibilidad, cursos de una sección en el mismo semestre (clique) y un prerrequisito.
+func testInstance() ([]domain.Activity, []domain.Room, []domain.Teacher, map[string]map[string]int, map[string][]string) {
+	var activities []domain.Activity
+	add := func(course string, eventType domain.EventCategory, eventNum int, sections []int, students int, teachers []string, sessions, duration int) {
+		siblingGroup := ""
+		if eventType == domain.CAT {
+			siblingGroup = loader.SiblingGroupID(course, sections)
+		}
+		for s := 1; s <= sessions; s++ {
+			code := fmt.Sprintf("%s-%s%d-S%d", course, eventTypeToString(eventType), eventNum, s)
+			activities = append(activities, domain.NewActivity(len(activities), code, course, course, eventType, eventNum, sections, students, teachers, siblingGroup, duration))
+		}
+	}
+	add("CIT1000", domain.CAT, 1, []int{1}, 45, []string{"Ana"}, 2, 1)
+	add("CIT1000", domain.AY, 1, []int{1}, 45, []string{"Diego"}, 1, 1)
+	add("CIT1010", domain.CAT, 1, []int{1}, 40, []string{"Bruno"}, 2, 1)
+	add("CIT1010", domain.LAB, 1, []int{1}, 25, []string{"Bruno", "Ana"}, 1, 2)
+	add("CIT2000", domain.CAT, 1, []int{1, 2}, 55, []string{"Carla"}, 2, 1)
+	add("CIT2000", domain.CAT, 2, []int{3}, 30, []string{"Bruno"}, 2, 1)
+	add("CIT2000", domain.AY, 1, []int{1, 2, 3}, 50, []string{"Diego"}, 1, 1)
+	add("CIT2010", domain.CAT, 1, []int{1}, 35, []string{"Carla"}, 3, 1)
+
+	rooms := []domain.Room{
+		{ID: 0, Code: "101", Capacity: 40, Type: domain.RoomClassroom},
+		{ID: 1, Code: "102", Capacity: 60, Type: domain.RoomClassroom},
+		{ID: 2, Code: "201", Capacity: 30, Type: domain.RoomClassroom},
+		{ID: 3, Code: "LAB A", Capacity: 30, Type: domain.RoomLab},
+	}
+
+	teachers := []domain.Teacher{
+		{ID: 0, Name: "Ana", Availability: domain.Availability{
+			Unavailable: map[int][]domain.SlotRange{0: {{From: 0, To: 2}}},
+			Preferences: map[int][]domain.SlotPreference{
+				1: {{SlotRange: domain.SlotRange{From: 0, To: 3}, Level: domain.StronglyPreferred}},
+				4: {{SlotRange: domain.SlotRange{From: 4, To: 6}, Level: domain.Undesired}},
+			},
+		}},
+		{ID: 1, Name: "Bruno", Availability: domain.Availability{
+			Preferences: map[int][]domain.SlotPreference{
+				0: {{SlotRange: domain.SlotRange{From: 0, To: 6}, Level: domain.StronglyUndesired}},
+				3: {{SlotRange: domain.SlotRange{From: 2, To: 4}, Level: domain.Preferred}},
+			},
+		}},
+		{ID: 2, Name: "Carla", Availability: domain.Availability{
+			AvailableOnly: map[int][]domain.SlotRange{
+				0: {{From: 0, To: 6}},
+				1: {{From: 0, To: 6}},
+				2: {{From: 0, To: 6}},
+				3: {{From: 0, To: 6}},
+			},
+		}},
+		{ID: 3, Name: "Diego"},
+	}
+
+	planLocations := map[string]map[string]int{
+		"CIT1000": {"EIT": 1},
+		"CIT1010": {"EIT": 1},
+		"CIT2000": {"EIT": 2},
+		"CIT2010": {"EIT": 2, "ICC": 3},
+	}
+	prerequisites := map[string][]string{"CIT2000": {"CIT1000"}}
+	return activities, rooms, teachers, planLocations, prerequisites
+}
+
+// TestMoveDeltaMatchesTotal aplica movimientos aleatorios de todos los tipos y verifica que el costo acumulado con
+// los deltas incrementales coincida con el recálculo completo de la función objetivo tras cada movimiento.
+func TestMoveDeltaMatchesTotal(t *testing.T) {
+	cases := []struct {
+		name       string
+		infeasible bool // partir con todas las actividades en el mismo bloque y sala
+		anchored   bool // cobrar los movimientos respecto del horario inicial (re-optimización)
+	}{
+		{name: "factible"},
+		{name: "factible con anclas", anchored: true},
+		{name: "con violaciones duras", infeasible: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			activities, rooms, teachers, planLocations, prerequisites := testInstance()
+			for i := range activities {
+				activities[i].Block = -1
+				if tc.infeasible {
+					activities[i].Block = 0
+					activities[i].Room = "102"
+				}
+			}
+
+			var anchors map[string]Anchor
+			if tc.anchored {
+				// el horario reparado es el "publicado"
+				published := domain.DefaultObjectiveWeights()
+				newSearchState(activities, rooms, &published, nil, prerequisites, planLocations, nil, nil, teachers)
+				anchors = AnchorsFrom(activities)
+			}
+
+			weights := domain.DefaultObjectiveWeights()
+			st := newSearchState(activities, rooms, &weights, anchors, prerequisites, planLocations, nil, nil, teachers)
+			if tc.infeasible && st.obj.hard == 0 {
+				t.Fatalf("se esperaban violaciones duras en el horario inicial")
+			}
+			if !tc.infeasible && st.obj.hard != 0 {
+				t.Fatalf("la reparación dejó %d violaciones duras", st.obj.hard)
+			}
+
+			rng := rand.New(rand.NewSource(1))
+			nb := newNeighborhood(st.obj, rooms, st.roomMap, nil, rng)
+			cost := st.initialCost
+			applied := make(map[string]int)
+
+			for iter := 0; iter < 5000; iter++ {
+				a := &activities[rng.Intn(len(activities))]
+				if a.Block < 0 {
+					continue
+				}
+				move := MoveNames[rng.Intn(len(MoveNames))]
+
+				switch move {
+				case MoveSwap, MoveSibling, MoveKempe:
+					var placements []placement
+					switch move {
+					case MoveSwap:
+						placements = nb.swapMove(a)
+					case MoveSibling:
+						placements = nb.siblingMove(a)
+					default:
+						placements = nb.kempeMove(a)
+					}
+					delta, undo, ok := nb.tryPlacements(placements)
+					if !ok {
+						continue
+					}
+					// deshacer a veces, como un movimiento rechazado: el costo no cambia
+					if rng.Intn(2) == 0 {
+						nb.undoPlacements(undo)
+					} else {
+						cost += delta
+					}
+				default:
+					block, room, ok := st.simpleMove(a, move, rng)
+					if !ok {
+						continue
+					}
+					delta, hardDelta := st.obj.moveDelta(a, block, room)
+					st.obj.apply(a, block, room, hardDelta)
+					cost += delta
+				}
+				applied[move]++
+
+				if full := st.obj.total(); math.Abs(full-cost) > 1e-6 {
+					t.Fatalf("iteración %d (%s %s): costo incremental %.4f, recálculo %.4f", iter, move, a.Code, cost, full)
+				}
+				if hard := countTotalHardViolations(activities, st.blockOcc, st.cliqueConflicts, st.teacherIndex, st.courseMajors); hard != st.obj.hard {
+					t.Fatalf("iteración %d (%s %s): %d violaciones duras incrementales, %d recontadas", iter, move, a.Code, st.obj.hard, hard)
+				}
+			}
+
+			expected := MoveNames
+			if tc.infeasible {
+				// los movimientos compuestos solo se aplican con el horario factible
+				expected = []string{MoveBlock, MoveRoom}
+			}
+			for _, name := range expected {
+				if applied[name] == 0 {
+					t.Errorf("el movimiento %s nunca se aplicó", name)
+				}
+			}
+		})
+	}
+}
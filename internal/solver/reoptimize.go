@@ -0,0 +1,192 @@
+package solver
+
+import (
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// Anchor es el bloque y la sala de una actividad en el horario publicado. Al re-optimizar, la función objetivo
+// cobra Weights.MovedBlock y Weights.MovedRoom a cada actividad que termina fuera de su ancla.
+type Anchor struct {
+	Block int
+	Room  string
+}
+
+// AnchorsFrom retorna el ancla (bloque y sala actuales) de cada actividad programada, por código
+func AnchorsFrom(activities []domain.Activity) map[string]Anchor {
+	anchors := make(map[string]Anchor, len(activities))
+	for i := range activities {
+		a := &activities[i]
+		if a.Block >= 0 {
+			anchors[a.Code] = Anchor{Block: a.Block, Room: a.Room}
+		}
+	}
+	return anchors
+}
+
+// ReleasedPlacement es una actividad cuyo bloque o sala del horario publicado ya no es válido con las entradas
+// actuales, y que la re-optimización debe volver a ubicar
+type ReleasedPlacement struct {
+	Code   string
+	Block  int       // bloque publicado
+	Room   string    // sala publicada
+	Reason DUDReason // por qué se liberó
+	Other  string    // actividad con la que choca, si corresponde
+	Freed  bool      // true si se liberó el bloque (y la sala), false si solo se cambió de sala
+}
+
+// ReleaseInvalidPlacements prepara un horario publicado para re-optimizarlo con las entradas actuales. Libera el
+// bloque de las actividades que ya no caben en el día, que quedaron en un periodo bloqueado o fuera de la
+// disponibilidad de su profesor, o que chocan (profesor, sección, semestre) con una actividad anterior que se
+// mantiene. Las que quedaron en una sala que ya no existe, no está permitida, no tiene capacidad o comparte con
+// otra actividad pasan a la sala libre más ajustada del mismo bloque, o se liberan si no hay ninguna.
+// SA inserta las liberadas con la reparación y cobra el movimiento desde su ancla.
+func ReleaseInvalidPlacements(activities []domain.Activity, rooms []domain.Room, constraints loader.RoomConstraints, teachers []domain.Teacher, planLocations map[string]map[string]int, electives map[string]bool) []ReleasedPlacement {
+	cliqueConflicts := buildCliqueMap(activities, planLocations, electives)
+	teacherIndex := buildTeacherIndex(teachers)
+	courseMajors := buildCourseMajors(planLocations)
+
+	var released []ReleasedPlacement
+	var roomless [][2]int // actividad e índice en released de las que mantienen el bloque pero perdieron la sala
+	blockOcc := make(map[int][]*domain.Activity)
+	roomCal := domain.NewRoomCalendar()
+
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 {
+			continue
+		}
+		release := ReleasedPlacement{Code: a.Code, Block: a.Block, Room: a.Room, Freed: true}
+
+		// Bloque: las actividades que se mantienen son las anteriores en la oferta, así de un choque se mueve una
+		if !domain.FitsInDay(a.Block, a.Duration) {
+			release.Reason = ReasonOutsideDay
+		} else {
+			for _, v := range hardViolationsAt(a, a.Block, a.Room, blockOcc, cliqueConflicts, teacherIndex, courseMajors) {
+				if v.Kind != ReasonNoRoom {
+					release.Reason, release.Other = v.Kind, v.Other
+					break
+				}
+			}
+		}
+		if release.Reason != "" {
+			released = append(released, release)
+			a.Block, a.Room = -1, ""
+			continue
+		}
+
+		// Sala: se busca otra cuando todas las salas válidas del horario publicado ya están reservadas
+		if a.Room != "" {
+			release.Reason, release.Freed = ReasonNoRoom, false
+			for _, r := range allowedRoomsFor(a, rooms, constraints) {
+				if r.Code != a.Room {
+					continue
+				}
+				switch {
+				case a.Students > r.Capacity:
+					release.Reason = ReasonCapacity
+				case !roomCal.IsFree(a, a.Room, a.Block):
+					release.Reason = ReasonRoomsBusy
+				default:
+					release.Reason = ""
+				}
+			}
+			if release.Reason != "" {
+				roomless = append(roomless, [2]int{i, len(released)})
+				released = append(released, release)
+				a.Room = ""
+			}
+		}
+		addToOccupancy(a, a.Block, a.Room, blockOcc, roomCal)
+	}
+
+	for _, p := range roomless {
+		a, k := &activities[p[0]], p[1]
+		var candidates []domain.Room
+		for _, r := range allowedRoomsFor(a, rooms, constraints) {
+			if a.Students <= r.Capacity {
+				candidates = append(candidates, r)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Capacity < candidates[j].Capacity })
+
+		if room := freeRoomFor(a, a.Block, candidates, roomCal); room != "" {
+			a.Room = room
+			roomCal.Reserve(a, room, a.Block)
+			continue
+		}
+		released[k].Freed = true
+		removeFromOccupancy(a, a.Block, "", blockOcc, roomCal)
+		a.Block = -1
+	}
+
+	sort.Slice(released, func(i, j int) bool { return released[i].Code < released[j].Code })
+	return released
+}
+
+// movedTerm: actividades fuera del bloque o sala del horario publicado (solo al re-optimizar)
+type movedTerm struct{}
+
+func (movedTerm) name() string { return "moved" }
+
+func (movedTerm) cost(o *objective, a *domain.Activity, block int, room string) float64 {
+	anchor, ok := o.anchors[a.ID]
+	if !ok {
+		return 0
+	}
+	cost := 0.0
+	if block != anchor.Block {
+		cost += o.w.MovedBlock
+	}
+	if room != anchor.Room {
+		cost += o.w.MovedRoom
+	}
+	return cost
+}
+
+func (t movedTerm) full(o *objective) float64 {
+	cost := 0.0
+	for i := range o.activities {
+		a := &o.activities[i]
+		cost += t.cost(o, a, a.Block, a.Room)
+	}
+	return cost
+}
+
+func (t movedTerm) delta(o *objective, a *domain.Activity, block int, room string) float64 {
+	return t.cost(o, a, block, room) - t.cost(o, a, a.Block, a.Room)
+}
+
+// setAnchors activa el término de perturbación con las anclas por código; sin anclas la función objetivo no cambia
+func (o *objective) setAnchors(anchors map[string]Anchor) {
+	if len(anchors) == 0 {
+		return
+	}
+	o.anchors = make(map[int]Anchor, len(anchors))
+	for i := range o.activities {
+		if anchor, ok := anchors[o.activities[i].Code]; ok {
+			o.anchors[o.activities[i].ID] = anchor
+		}
+	}
+	o.terms = append(o.terms, movedTerm{})
+}
+
+// countMoved cuenta las actividades con ancla que cambiaron de bloque y las que solo cambiaron de sala
+func (o *objective) countMoved() (int, int) {
+	blocks, rooms := 0, 0
+	for i := range o.activities {
+		a := &o.activities[i]
+		anchor, ok := o.anchors[a.ID]
+		if !ok {
+			continue
+		}
+		if a.Block != anchor.Block {
+			blocks++
+		} else if a.Room != anchor.Room {
+			rooms++
+		}
+	}
+	return blocks, rooms
+}
@@ -0,0 +1,159 @@
+package solver
+
+import (
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// HardViolation es una restricción dura que el horario no cumple
+type HardViolation struct {
+	ActivityCode string
+	Block        int
+	Room         string
+	Kind         DUDReason // SIN_SALA, PERIODO_BLOQUEADO, PROFESOR_NO_DISPONIBLE, PROFESOR_OCUPADO, SECCION o SEMESTRE
+	Other        string    // actividad con la que choca (solo conflictos entre actividades)
+}
+
+// repairUnscheduled inserta en el estado de SA las actividades que el scheduler dejó sin bloque (DUD).
+// Cada una va al bloque con menos violaciones duras, con la sala permitida más chica que esté libre;
+// si no hay sala libre queda sin sala, lo que también cuenta como violación. Retorna cuántas se
+// insertaron sin violaciones.
+func repairUnscheduled(activities []domain.Activity, rooms []domain.Room, constraints loader.RoomConstraints, blockOcc map[int][]*domain.Activity, roomCal *domain.RoomCalendar, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) int {
+	feasible := 0
+
+	for i := range activities {
+		a := &activities[i]
+		if a.Block >= 0 {
+			continue
+		}
+		duration := a.Span()
+
+		// Salas candidatas de menor a mayor capacidad (best-fit, como el scheduler)
+		var candidates []domain.Room
+		for _, r := range allowedRoomsFor(a, rooms, constraints) {
+			if a.Students <= r.Capacity {
+				candidates = append(candidates, r)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Capacity < candidates[j].Capacity })
+
+		bestBlock, bestRoom, bestViolations := -1, "", 0
+		for b := 0; b < domain.TotalBlocks; b++ {
+			if !domain.FitsInDay(b, duration) {
+				continue
+			}
+			room := freeRoomFor(a, b, candidates, roomCal)
+			v := countHardViolations(a, b, room, blockOcc, cliqueConflicts, teacherIndex, courseMajors)
+			if bestBlock < 0 || v < bestViolations {
+				bestBlock, bestRoom, bestViolations = b, room, v
+			}
+			if v == 0 {
+				break
+			}
+		}
+		if bestBlock < 0 {
+			continue // la actividad no cabe en ningún día de la grilla
+		}
+
+		a.Block = bestBlock
+		a.Room = bestRoom
+		addToOccupancy(a, bestBlock, bestRoom, blockOcc, roomCal)
+		if bestViolations == 0 {
+			feasible++
+		}
+	}
+	return feasible
+}
+
+// freeRoomFor retorna la primera sala candidata libre en todos los bloques que ocuparía la actividad
+func freeRoomFor(a *domain.Activity, block int, candidates []domain.Room, roomCal *domain.RoomCalendar) string {
+	for _, r := range candidates {
+		if !isRoomBusy(a, block, r.Code, roomCal) {
+			return r.Code
+		}
+	}
+	return ""
+}
+
+// isRoomBusy verifica si otra actividad ocupa la sala en alguno de los bloques que ocuparía la actividad
+func isRoomBusy(a *domain.Activity, block int, room string, roomCal *domain.RoomCalendar) bool {
+	return room != "" && !roomCal.IsFree(a, room, block)
+}
+
+// countHardViolations cuenta las violaciones duras de la actividad en el bloque y sala dados: sin sala,
+// periodo bloqueado, profesor no disponible, y una por cada actividad con la que choca (profesor, sección
+// o clique de semestre). Un choque entre dos actividades aparece en el conteo de ambas, por lo que mover una
+// actividad cambia el total del horario exactamente en la diferencia de su conteo.
+func countHardViolations(a *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) int {
+	return len(hardViolationsAt(a, block, room, blockOcc, cliqueConflicts, teacherIndex, courseMajors))
+}
+
+// hardViolationsAt lista las violaciones duras de la actividad en el bloque y sala dados
+func hardViolationsAt(a *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) []HardViolation {
+	var violations []HardViolation
+	add := func(kind DUDReason, other string) {
+		violations = append(violations, HardViolation{ActivityCode: a.Code, Block: block, Room: room, Kind: kind, Other: other})
+	}
+
+	if room == "" {
+		add(ReasonNoRoom, "")
+	}
+	if isBlockedForActivity(a, block, courseMajors) {
+		add(ReasonBlockedPeriod, "")
+	}
+	if isTeacherBusy(a, block, teacherIndex) {
+		add(ReasonTeacherUnavailable, "")
+	}
+
+	seen := make(map[int]bool)
+	for d := 0; d < a.Span(); d++ {
+		for _, other := range blockOcc[block+d] {
+			if other.ID == a.ID || seen[other.ID] {
+				continue
+			}
+			switch {
+			case a.SharesTeacher(other):
+				add(ReasonTeacherBusy, other.Code)
+			case a.SharesSection(other):
+				add(ReasonSection, other.Code)
+			case cliqueConflicts[a.CourseCode] != nil && cliqueConflicts[a.CourseCode][other.CourseCode]:
+				add(ReasonSemester, other.Code)
+			default:
+				continue
+			}
+			seen[other.ID] = true
+		}
+	}
+	return violations
+}
+
+// collectHardViolations lista las violaciones duras del horario, cada choque entre actividades una sola vez
+func collectHardViolations(activities []domain.Activity, blockOcc map[int][]*domain.Activity, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) []HardViolation {
+	codeToID := make(map[string]int, len(activities))
+	for i := range activities {
+		codeToID[activities[i].Code] = activities[i].ID
+	}
+
+	var all []HardViolation
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 {
+			all = append(all, HardViolation{ActivityCode: a.Code, Block: a.Block, Kind: ReasonNoRoom})
+			continue
+		}
+		for _, v := range hardViolationsAt(a, a.Block, a.Room, blockOcc, cliqueConflicts, teacherIndex, courseMajors) {
+			if v.Other != "" && codeToID[v.Other] < a.ID {
+				continue // ya reportado desde la otra actividad
+			}
+			all = append(all, v)
+		}
+	}
+	return all
+}
+
+// countTotalHardViolations retorna el total de violaciones duras con el mismo criterio que countHardViolations
+func countTotalHardViolations(activities []domain.Activity, blockOcc map[int][]*domain.Activity, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) int {
+	return len(collectHardViolations(activities, blockOcc, cliqueConflicts, teacherIndex, courseMajors))
+}
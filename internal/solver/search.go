@@ -0,0 +1,164 @@
+package solver
+
+import (
+	"math/rand"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// searchState reúne lo que comparten las metaheurísticas de mejora (SA y búsqueda tabú): los índices del problema,
+// la ocupación de bloques y salas, la reparación de actividades DUD y la función objetivo.
+type searchState struct {
+	activities  []domain.Activity
+	rooms       []domain.Room
+	roomMap     map[string]domain.Room
+	constraints loader.RoomConstraints
+	teachers    []domain.Teacher
+
+	siblingGroups   map[string][]*domain.Activity
+	prereqPairs     []PrereqPair
+	cliqueConflicts map[string]map[string]bool
+	teacherIndex    map[string]*domain.Teacher
+	courseMajors    map[string][]string
+
+	blockOcc map[int][]*domain.Activity
+	roomCal  *domain.RoomCalendar // sala × bloque -> actividad
+
+	obj *objective
+
+	unscheduled int
+	repaired    int
+	initialHard int
+	initialCost float64
+
+	moveStats map[string]*MoveStats
+}
+
+// newSearchState construye los índices, repara las actividades DUD y prepara la función objetivo con los pesos dados.
+// Con anclas (re-optimización) la función objetivo cobra además cada actividad movida de su bloque o sala publicados.
+func newSearchState(activities []domain.Activity, rooms []domain.Room, weights *domain.ObjectiveWeights, anchors map[string]Anchor, prerequisites map[string][]string, planLocations map[string]map[string]int, electives map[string]bool, constraints loader.RoomConstraints, teachers []domain.Teacher) *searchState {
+	s := &searchState{
+		activities:  activities,
+		rooms:       rooms,
+		constraints: constraints,
+		teachers:    teachers,
+		moveStats:   make(map[string]*MoveStats, len(MoveNames)),
+	}
+	for _, name := range MoveNames {
+		s.moveStats[name] = &MoveStats{}
+	}
+
+	// Construir índices útiles
+	s.siblingGroups = buildSiblingIndex(activities)
+	s.prereqPairs = buildPrereqPairs(prerequisites, buildCourseIndex(activities))
+
+	// Construir mapa de cliques de semestre para validación rápida (sin electivos)
+	s.cliqueConflicts = buildCliqueMap(activities, planLocations, electives)
+
+	// Índice de salas por código para validación rápida
+	s.roomMap = buildRoomMap(rooms)
+
+	// Índice de profesores para validar disponibilidad
+	s.teacherIndex = buildTeacherIndex(teachers)
+
+	// Carreras de cada curso para los periodos bloqueados por carrera
+	s.courseMajors = buildCourseMajors(planLocations)
+
+	// Índice de actividades por bloque y sala
+	s.blockOcc = buildBlockOccupancy(activities)
+	s.roomCal = domain.BuildRoomCalendar(activities)
+
+	// Reparación: insertar las actividades DUD en el bloque con menos violaciones duras
+	for i := range activities {
+		if activities[i].Block < 0 {
+			s.unscheduled++
+		}
+	}
+	if s.unscheduled > 0 {
+		s.repaired = repairUnscheduled(activities, rooms, constraints, s.blockOcc, s.roomCal, s.cliqueConflicts, s.teacherIndex, s.courseMajors)
+	}
+	s.initialHard = countTotalHardViolations(activities, s.blockOcc, s.cliqueConflicts, s.teacherIndex, s.courseMajors)
+
+	// Función objetivo: una sola definición para el costo total y los deltas
+	s.obj = newObjective(activities, weights, s.siblingGroups, s.prereqPairs, s.teacherIndex, s.blockOcc, s.roomCal, s.cliqueConflicts, s.courseMajors)
+	s.obj.setAnchors(anchors)
+	s.initialCost = s.obj.total()
+	return s
+}
+
+// simpleMove propone un movimiento block (otro bloque) o room (otra sala) para la actividad y retorna su destino,
+// o false si no es válido. Con el horario factible se descartan los movimientos con conflictos; con violaciones
+// pendientes la función objetivo las cuenta para que la búsqueda pueda ir eliminándolas.
+func (s *searchState) simpleMove(activity *domain.Activity, move string, rng *rand.Rand) (int, string, bool) {
+	newBlock := activity.Block
+	newRoom := activity.Room
+
+	if move == MoveBlock {
+		newBlock = rng.Intn(domain.TotalBlocks)
+		if newBlock == activity.Block {
+			return 0, "", false
+		}
+
+		if s.obj.hard == 0 {
+			if hasConflictInBlockWithRoom(activity, newBlock, activity.Room, s.blockOcc, s.roomCal, s.cliqueConflicts, s.teacherIndex, s.courseMajors) {
+				return 0, "", false
+			}
+		} else {
+			if !domain.FitsInDay(newBlock, activity.Duration) || isRoomBusy(activity, newBlock, activity.Room, s.roomCal) {
+				return 0, "", false
+			}
+			// una actividad sin sala intenta llevarse una sala libre del nuevo bloque
+			if newRoom == "" {
+				newRoom = selectValidRoom(activity, newBlock, s.rooms, s.roomMap, s.constraints, s.roomCal, rng)
+			}
+		}
+	} else {
+		// La sala queda validada por selectValidRoom
+		newRoom = selectValidRoom(activity, activity.Block, s.rooms, s.roomMap, s.constraints, s.roomCal, rng)
+		if newRoom == "" || newRoom == activity.Room {
+			return 0, "", false
+		}
+	}
+	return newBlock, newRoom, true
+}
+
+// result calcula las métricas finales del horario
+func (s *searchState) result(iterations, improvements int) SAResult {
+	activities := s.activities
+
+	remaining := collectHardViolations(activities, s.blockOcc, s.cliqueConflicts, s.teacherIndex, s.courseMajors)
+	costTerms := s.obj.breakdown()
+	satisfaction := domain.CalculateTeacherSatisfaction(activities, s.teachers)
+	movedBlocks, movedRooms := s.obj.countMoved()
+	moveStats := make(map[string]MoveStats, len(s.moveStats))
+	for name, stats := range s.moveStats {
+		moveStats[name] = *stats
+	}
+
+	return SAResult{
+		InitialCost:     s.initialCost,
+		FinalCost:       s.obj.total(),
+		CostTerms:       costTerms,
+		Iterations:      iterations,
+		Improvements:    improvements,
+		MirrorPenalty:   calculateMirrorPenalty(activities, s.siblingGroups),
+		WednesdayBonus:  calculateWednesdayBonus(activities),
+		PrereqBonus:     calculatePrereqBonus(activities, s.prereqPairs),
+		RoomConsistency: calculateRoomConsistency(activities, s.siblingGroups),
+		DaySeparation:   calculateDaySeparationMetric(activities, s.siblingGroups),
+
+		TeacherSatisfaction:    satisfaction,
+		AvgTeacherSatisfaction: domain.AverageSatisfaction(satisfaction),
+
+		Unscheduled:    s.unscheduled,
+		Repaired:       s.repaired,
+		InitialHard:    s.initialHard,
+		HardViolations: remaining,
+
+		MovedBlocks: movedBlocks,
+		MovedRooms:  movedRooms,
+
+		MoveStats: moveStats,
+	}
+}
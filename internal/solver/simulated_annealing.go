@@ -3,7 +3,7 @@ package solver
 import (
 	"math"
 	"math/rand"
-	"strconv"
+	"time"
 
 	"timetabling-UDP/internal/domain"
 	"timetabling-UDP/internal/loader"
@@ -16,6 +16,16 @@ type SAConfig struct {
 	CoolingRate    float64 // Tasa de enfriamiento
 	MinTemp        float64 // Temperatura mínima para parar
 	IterationsPerT int     // Iteraciones por nivel de temperatura
+
+	Weights domain.ObjectiveWeights // Pesos de los términos de la función objetivo
+
+	Seed int64 // Semilla del generador aleatorio (0 = aleatoria)
+
+	CheckEvery int // Cada cuántas iteraciones comparar el costo incremental con el recálculo completo (0 = nunca)
+
+	Moves MoveProbabilities // Probabilidad relativa de cada tipo de movimiento (nil = DefaultMoveProbabilities)
+
+	Anchors map[string]Anchor // Horario publicado a perturbar lo menos posible, por código (nil = optimizar libremente)
 }
 
 // DefaultSAConfig retorna configuración por defecto con más iteraciones.
@@ -25,6 +35,9 @@ func DefaultSAConfig() SAConfig {
 		CoolingRate:    0.999,
 		MinTemp:        0.01,
 		IterationsPerT: 5000,
+
+		Weights: domain.DefaultObjectiveWeights(),
+		Moves:   DefaultMoveProbabilities(),
 	}
 }
 
@@ -32,6 +45,7 @@ func DefaultSAConfig() SAConfig {
 type SAResult struct {
 	InitialCost     float64
 	FinalCost       float64
+	CostTerms       map[string]float64 // Costo final de cada término de la función objetivo
 	Iterations      int
 	Improvements    int
 	MirrorPenalty   float64
@@ -39,123 +53,127 @@ type SAResult struct {
 	PrereqBonus     float64 // Porcentaje de pares prereq en mismo bloque
 	RoomConsistency float64 // Porcentaje de hermanos en misma sala
 	DaySeparation   float64 // Porcentaje de CAT con separación ideal
-}
 
-// SimulatedAnnealing ejecuta el algoritmo de Simulated Annealing para optimizar la asignación de bloques y salas.
-func SimulatedAnnealing(activities []domain.Activity, rooms []domain.Room, config SAConfig, prerequisites map[string][]string, planLocations map[string]map[string]int, electives map[string]bool, constraints loader.RoomConstraints) SAResult {
+	TeacherSatisfaction    []domain.TeacherSatisfaction // Satisfacción por profesor con preferencias
+	AvgTeacherSatisfaction float64                      // Promedio de satisfacción de profesores
+
+	Unscheduled    int             // Actividades que llegaron sin programar (DUD del scheduler)
+	Repaired       int             // De ellas, las que la reparación insertó sin violaciones
+	InitialHard    int             // Violaciones duras tras la reparación, antes de SA
+	HardViolations []HardViolation // Violaciones duras que quedan en el horario final
 
-	// Construir índices útiles
-	siblingGroups := buildSiblingIndex(activities)
-	courseActivities := buildCourseIndex(activities)
-	prereqPairs := buildPrereqPairs(prerequisites, courseActivities)
+	MovedBlocks int // Actividades con ancla que cambiaron de bloque (re-optimización)
+	MovedRooms  int // Actividades con ancla que solo cambiaron de sala
+
+	MoveStats map[string]MoveStats // Intentos, factibles, aceptados y mejoras por tipo de movimiento
+}
 
-	// Construir mapa de cliques de semestre para validación rápida (sin electivos)
-	cliqueConflicts := buildCliqueMap(activities, planLocations, electives)
+// SimulatedAnnealing ejecuta el algoritmo de Simulated Annealing para optimizar la asignación de bloques y salas.
+// Las actividades sin bloque (DUD del scheduler) se insertan primero con una fase de reparación; mientras el
+// horario tenga violaciones duras, cada una cuesta config.Weights.Hard y SA las va eliminando.
+func SimulatedAnnealing(activities []domain.Activity, rooms []domain.Room, config SAConfig, prerequisites map[string][]string, planLocations map[string]map[string]int, electives map[string]bool, constraints loader.RoomConstraints, teachers []domain.Teacher) SAResult {
+	st := newSearchState(activities, rooms, &config.Weights, config.Anchors, prerequisites, planLocations, electives, constraints, teachers)
+	obj := st.obj
 
-	// Índice de salas por código para validación rápida
-	roomMap := buildRoomMap(rooms)
+	// Generador aleatorio propio para poder reproducir una ejecución
+	rng := newRand(config.Seed)
 
-	// Calcular costo inicial (ahora incluye room consistency)
-	initialCost := calculateTotalCostWithRooms(activities, siblingGroups, prereqPairs)
+	// Vecindario de movimientos compuestos
+	if config.Moves == nil {
+		config.Moves = DefaultMoveProbabilities()
+	}
+	nb := newNeighborhood(obj, rooms, st.roomMap, constraints, rng)
 
 	// SA loop
 	temperature := config.InitialTemp
-	currentCost := initialCost
+	currentCost := st.initialCost
 	iterations := 0
 	improvements := 0
 
-	// Índice de actividades por bloque y sala
-	blockOccupancy := buildBlockOccupancy(activities)
-	roomBlockOccupancy := buildRoomBlockOccupancy(activities) // room+block -> activity
-
 	for temperature > config.MinTemp {
 		for i := 0; i < config.IterationsPerT; i++ {
 			iterations++
+			if config.CheckEvery > 0 && iterations%config.CheckEvery == 0 {
+				obj.check(currentCost, iterations)
+			}
 
 			// Seleccionar actividad aleatoria
-			idx := rand.Intn(len(activities))
+			idx := rng.Intn(len(activities))
 			activity := &activities[idx]
+			if activity.Block < 0 {
+				continue // no cabe en ningún día de la grilla
+			}
 
-			// 50% probabilidad de mover bloque, 50% de mover sala
-			moveType := rand.Intn(2)
-
-			if moveType == 0 {
-				newBlock := rand.Intn(domain.TotalBlocks)
-				oldBlock := activity.Block
-
-				if newBlock == oldBlock {
-					continue
+			move := config.Moves.pick(rng)
+			stats := st.moveStats[move]
+			stats.Attempted++
+
+			// Movimientos compuestos: varias actividades a la vez, solo sobre un horario factible
+			if move == MoveSwap || move == MoveSibling || move == MoveKempe {
+				var placements []placement
+				switch move {
+				case MoveSwap:
+					placements = nb.swapMove(activity)
+				case MoveSibling:
+					placements = nb.siblingMove(activity)
+				case MoveKempe:
+					placements = nb.kempeMove(activity)
 				}
-
-				// Verificar hard constraints para nuevo bloque
-				if hasConflictInBlockWithRoom(activity, newBlock, activity.Room, blockOccupancy, roomBlockOccupancy, cliqueConflicts) {
+				delta, undo, ok := nb.tryPlacements(placements)
+				if !ok {
 					continue
 				}
+				stats.Feasible++
 
-				// Calcular delta de costo
-				oldCost := activityCostForBlockAndRoom(activity, oldBlock, activity.Room, siblingGroups)
-				newCostVal := activityCostForBlockAndRoom(activity, newBlock, activity.Room, siblingGroups)
-				delta := newCostVal - oldCost
-
-				if delta < 0 || rand.Float64() < math.Exp(-delta/temperature) {
-					removeFromOccupancy(activity, oldBlock, activity.Room, blockOccupancy, roomBlockOccupancy)
-					activity.Block = newBlock
-					addToOccupancy(activity, newBlock, activity.Room, blockOccupancy, roomBlockOccupancy)
-
+				if delta < 0 || rng.Float64() < math.Exp(-delta/temperature) {
 					currentCost += delta
+					stats.Accepted++
 					if delta < 0 {
 						improvements++
+						stats.Improving++
 					}
+				} else {
+					nb.undoPlacements(undo)
 				}
-			} else {
-				newRoom := selectValidRoom(activity, activity.Block, rooms, roomMap, constraints, roomBlockOccupancy)
-				if newRoom == "" || newRoom == activity.Room {
-					continue
-				}
+				continue
+			}
 
-				// La sala ya fue validada
-				oldRoom := activity.Room
+			newBlock, newRoom, ok := st.simpleMove(activity, move, rng)
+			if !ok {
+				continue
+			}
+			stats.Feasible++
 
-				// Calcular delta de costo
-				oldCost := activityCostForBlockAndRoom(activity, activity.Block, oldRoom, siblingGroups)
-				newCostVal := activityCostForBlockAndRoom(activity, activity.Block, newRoom, siblingGroups)
-				delta := newCostVal - oldCost
+			// Calcular delta de costo
+			delta, hardDelta := obj.moveDelta(activity, newBlock, newRoom)
 
-				if delta < 0 || rand.Float64() < math.Exp(-delta/temperature) {
-					removeFromOccupancy(activity, activity.Block, oldRoom, blockOccupancy, roomBlockOccupancy)
-					activity.Room = newRoom
-					addToOccupancy(activity, activity.Block, newRoom, blockOccupancy, roomBlockOccupancy)
+			if delta < 0 || rng.Float64() < math.Exp(-delta/temperature) {
+				obj.apply(activity, newBlock, newRoom, hardDelta)
 
-					currentCost += delta
-					if delta < 0 {
-						improvements++
-					}
+				currentCost += delta
+				stats.Accepted++
+				if delta < 0 {
+					improvements++
+					stats.Improving++
 				}
 			}
 		}
 
 		temperature *= config.CoolingRate
 	}
+	if config.CheckEvery > 0 {
+		obj.check(currentCost, iterations)
+	}
 
-	// Calcular costos finales
-	finalCost := calculateTotalCostWithRooms(activities, siblingGroups, prereqPairs)
-	mirrorPenalty := calculateMirrorPenalty(activities, siblingGroups)
-	wednesdayBonus := calculateWednesdayBonus(activities)
-	prereqBonus := calculatePrereqBonus(activities, prereqPairs)
-	roomConsistency := calculateRoomConsistency(activities, siblingGroups)
-	daySeparation := calculateDaySeparationMetric(activities, siblingGroups)
-
-	return SAResult{
-		InitialCost:     initialCost,
-		FinalCost:       finalCost,
-		Iterations:      iterations,
-		Improvements:    improvements,
-		MirrorPenalty:   mirrorPenalty,
-		WednesdayBonus:  wednesdayBonus,
-		PrereqBonus:     prereqBonus,
-		RoomConsistency: roomConsistency,
-		DaySeparation:   daySeparation,
+	return st.result(iterations, improvements)
+}
+
+// newRand crea un generador con la semilla dada, o con una semilla basada en la hora si es 0
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
 	}
+	return rand.New(rand.NewSource(seed))
 }
 
 // PrereqPair representa un par de actividades que son prerrequisito/dependiente
@@ -214,10 +232,10 @@ func buildBlockOccupancy(activities []domain.Activity) map[int][]*domain.Activit
 	occ := make(map[int][]*domain.Activity)
 	for i := range activities {
 		a := &activities[i]
-		duration := a.Duration
-		if duration < 1 {
-			duration = 1
+		if a.Block < 0 {
+			continue // sin programar
 		}
+		duration := a.Span()
 		// Registrar en cada bloque que ocupa
 		for d := 0; d < duration; d++ {
 			b := a.Block + d
@@ -298,47 +316,13 @@ func buildSiblingIndex(activities []domain.Activity) map[string][]*domain.Activi
 	return index
 }
 
-// blockToDaySlot convierte bloque (0-34) a día y slot.
+// blockToDaySlot convierte bloque a día y slot según la grilla activa.
 func blockToDaySlot(block int) (day, slot int) {
 	day = block / domain.BlocksPerDay
 	slot = block % domain.BlocksPerDay
 	return
 }
 
-// calculateMirrorPenalty calcula la penalidad de espejo.
-func calculateMirrorPenalty(activities []domain.Activity, siblings map[string][]*domain.Activity) float64 {
-	penalty := 0.0
-	counted := make(map[string]bool)
-
-	for i := range activities {
-		a := &activities[i]
-		if a.SiblingGroupID == "" || counted[a.SiblingGroupID] {
-			continue
-		}
-		counted[a.SiblingGroupID] = true
-
-		sibs := siblings[a.SiblingGroupID]
-		if len(sibs) < 2 {
-			continue
-		}
-
-		// Verificar si todos están en espejo
-		_, baseSlot := blockToDaySlot(sibs[0].Block)
-		baseRoom := sibs[0].Room
-
-		for j := 1; j < len(sibs); j++ {
-			_, slot := blockToDaySlot(sibs[j].Block)
-			if slot != baseSlot {
-				penalty += 50.0
-			}
-			if sibs[j].Room != baseRoom {
-				penalty += 20.0
-			}
-		}
-	}
-	return penalty
-}
-
 // calculateWednesdayBonus calcula cuántas AY están en miércoles.
 func calculateWednesdayBonus(activities []domain.Activity) float64 {
 	ayOnWednesday := 0
@@ -348,7 +332,7 @@ func calculateWednesdayBonus(activities []domain.Activity) float64 {
 		if activities[i].Type == domain.AY {
 			totalAY++
 			day, _ := blockToDaySlot(activities[i].Block)
-			if day == 2 {
+			if day == domain.WednesdayDay {
 				ayOnWednesday++
 			}
 		}
@@ -369,47 +353,18 @@ func buildRoomMap(rooms []domain.Room) map[string]domain.Room {
 	return m
 }
 
-// buildRoomBlockOccupancy crea índice (room, block) -> actividad ocupante
-func buildRoomBlockOccupancy(activities []domain.Activity) map[string]*domain.Activity {
-	occ := make(map[string]*domain.Activity)
-	for i := range activities {
-		a := &activities[i]
-		duration := a.Duration
-		if duration < 1 {
-			duration = 1
-		}
-		// registrar en cada bloque que ocupa
-		for d := 0; d < duration; d++ {
-			b := a.Block + d
-			key := a.Room + ":" + strconv.Itoa(b)
-			occ[key] = a
-		}
-	}
-	return occ
-}
-
 // selectValidRoom selecciona una sala válida aleatoria para la actividad en el bloque dado, valida: RC3, RC4, RC5 y RC6
-func selectValidRoom(activity *domain.Activity, block int, rooms []domain.Room, roomMap map[string]domain.Room, constraints loader.RoomConstraints, roomBlockOcc map[string]*domain.Activity) string {
+func selectValidRoom(activity *domain.Activity, block int, rooms []domain.Room, roomMap map[string]domain.Room, constraints loader.RoomConstraints, roomCal *domain.RoomCalendar, rng *rand.Rand) string {
 	// obtener salas permitidas por restricción específica
 	eventType := eventTypeToString(activity.Type)
 	allowedCodes := constraints.GetAllowedRooms(activity.CourseCode, eventType)
 
-	duration := activity.Duration
-	if duration < 1 {
-		duration = 1
-	}
-
 	var validRooms []string
 
-roomLoop:
 	for _, room := range rooms {
 		// RC3
-		for d := 0; d < duration; d++ {
-			b := block + d
-			key := room.Code + ":" + strconv.Itoa(b)
-			if existing := roomBlockOcc[key]; existing != nil && existing.ID != activity.ID {
-				continue roomLoop
-			}
+		if !roomCal.IsFree(activity, room.Code, block) {
+			continue
 		}
 
 		// RC6
@@ -440,46 +395,38 @@ roomLoop:
 	}
 
 	// seleccionar aleatoriamente entre las válidas
-	return validRooms[rand.Intn(len(validRooms))]
+	return validRooms[rng.Intn(len(validRooms))]
 }
 
-// hasConflictInBlockWithRoom verifica conflictos considerando la sala propuesta y la duración de la actividad
-func hasConflictInBlockWithRoom(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomBlockOcc map[string]*domain.Activity, cliqueConflicts map[string]map[string]bool) bool {
-	duration := activity.Duration
-	if duration < 1 {
-		duration = 1
+// hasConflictInBlockWithRoom verifica conflictos considerando la sala propuesta, la duración de la actividad y la disponibilidad de sus profesores
+func hasConflictInBlockWithRoom(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomCal *domain.RoomCalendar, cliqueConflicts map[string]map[string]bool, teacherIndex map[string]*domain.Teacher, courseMajors map[string][]string) bool {
+	duration := activity.Span()
+
+	// validar que no cruce días ni exceda el último slot utilizable del día según la grilla
+	if !domain.FitsInDay(block, duration) {
+		return true // No cabe en el día
 	}
 
-	// validar que no cruce días: todos los bloques deben estar en el mismo día
-	startDay := block / domain.BlocksPerDay
-	endBlock := block + duration - 1
-	endDay := endBlock / domain.BlocksPerDay
-	if startDay != endDay {
-		return true
+	// validar que no ocupe periodos bloqueados (horario protegido del miércoles, periodos de la carrera)
+	// esto aplica tanto al bloque directo como a actividades multi-bloque que lo atraviesen
+	if isBlockedForActivity(activity, block, courseMajors) {
+		return true // ocuparía un periodo bloqueado
 	}
 
-	// validar que no exceda el último bloque del día
-	slotInDay := block % domain.BlocksPerDay
-	if slotInDay+duration > domain.BlocksPerDay {
-		return true // No cabe en el día
+	// validar que los profesores no estén ocupados en ninguno de los bloques
+	if isTeacherBusy(activity, block, teacherIndex) {
+		return true
 	}
 
-	// validar que no ocupe el bloque protegido del miércoles (11:30-12:50)
-	// esto aplica tanto al bloque directo como a actividades multi-bloque que lo atraviesen
-	if domain.OccupiesProtectedBlock(block, duration) {
-		return true // ocuparía el bloque protegido
+	// verificar ocupación de sala en todos los bloques
+	if room != "" && !roomCal.IsFree(activity, room, block) {
+		return true // sala ocupada en alguno de los bloques
 	}
 
-	// verificar cada bloque que ocuparía la actividad
+	// verificar conflictos con otras actividades en cada bloque que ocuparía la actividad
 	for i := 0; i < duration; i++ {
 		b := block + i
 
-		// verificar ocupación de sala en este bloque
-		key := room + ":" + strconv.Itoa(b)
-		if existing := roomBlockOcc[key]; existing != nil && existing.ID != activity.ID {
-			return true // sala ocupada en este bloque
-		}
-
 		// verificar conflictos con otras actividades en este bloque
 		for _, other := range blockOcc[b] {
 			if other.ID == activity.ID {
@@ -501,11 +448,8 @@ func hasConflictInBlockWithRoom(activity *domain.Activity, block int, room strin
 }
 
 // removeFromOccupancy limpia los indices de ocupación actual, se usa cuando SA mueve una actividad de un bloque a otro
-func removeFromOccupancy(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomBlockOcc map[string]*domain.Activity) {
-	duration := activity.Duration
-	if duration < 1 {
-		duration = 1
-	}
+func removeFromOccupancy(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomCal *domain.RoomCalendar) {
+	duration := activity.Span()
 
 	for i := 0; i < duration; i++ {
 		b := block + i
@@ -518,106 +462,19 @@ func removeFromOccupancy(activity *domain.Activity, block int, room string, bloc
 				break
 			}
 		}
-
-		// remover de roomBlockOcc
-		key := room + ":" + strconv.Itoa(b)
-		delete(roomBlockOcc, key)
 	}
+	roomCal.Release(activity, room, block)
 }
 
 // addToOccupancy agrega actividad a los índices considerando su duración, se usa cuando SA mueve una actividad de un bloque a otro
-func addToOccupancy(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomBlockOcc map[string]*domain.Activity) {
-	duration := activity.Duration
-	if duration < 1 {
-		duration = 1
-	}
+func addToOccupancy(activity *domain.Activity, block int, room string, blockOcc map[int][]*domain.Activity, roomCal *domain.RoomCalendar) {
+	duration := activity.Span()
 
 	for i := 0; i < duration; i++ {
 		b := block + i
 		blockOcc[b] = append(blockOcc[b], activity)
-		key := room + ":" + strconv.Itoa(b)
-		roomBlockOcc[key] = activity
-	}
-}
-
-// activityCostForBlockAndRoom calcula costo de actividad en bloque + sala
-// Incluye penalidades de espejo y separación de días
-func activityCostForBlockAndRoom(activity *domain.Activity, block int, room string, siblings map[string][]*domain.Activity) float64 {
-	cost := 0.0
-
-	// solo evaluar hermanos para cátedras
-	if activity.SiblingGroupID != "" && activity.Type == domain.CAT {
-		sibs := siblings[activity.SiblingGroupID]
-		myDay, mySlot := blockToDaySlot(block)
-
-		// filtrar solo hermanos CAT (no AY)
-		var catSibs []*domain.Activity
-		for _, sib := range sibs {
-			if sib.Type == domain.CAT {
-				catSibs = append(catSibs, sib)
-			}
-		}
-
-		for _, sib := range catSibs {
-			if sib.ID == activity.ID {
-				continue
-			}
-			sibDay, sibSlot := blockToDaySlot(sib.Block)
-
-			// penalidad por no estar en espejo
-			if sibSlot != mySlot {
-				cost += 50.0
-			}
-
-			// penalidad por hermano en distinta sala
-			if sib.Room != room {
-				cost += 30.0
-			}
-
-			daySeparation := abs(myDay - sibDay)
-
-			if len(catSibs) == 2 {
-				switch daySeparation {
-				case 3:
-					cost -= 20.0
-				case 2:
-					cost += 0.0
-				case 1:
-					cost += 25.0
-				case 0:
-					cost += 60.0
-				default:
-					cost += 10.0
-				}
-			} else if len(catSibs) >= 3 {
-				if daySeparation == 0 {
-					cost += 80.0 // muy malo: dos CAT el mismo día
-				} else if daySeparation == 1 {
-					cost += 15.0 // aceptable pero no ideal
-				}
-			}
-		}
-
-		// Verificar que CAT no esté el mismo día que su AY
-		for _, sib := range sibs {
-			if sib.Type == domain.AY {
-				ayDay, _ := blockToDaySlot(sib.Block)
-				if myDay == ayDay {
-					cost += 35.0 // Penalizar CAT mismo día que AY
-				}
-			}
-		}
-	}
-
-	// Bonus por AY en miércoles
-	if activity.Type == domain.AY {
-		day, _ := blockToDaySlot(block)
-		if day != 2 {
-			cost += 10.0 // Penalidad si AY NO está en miércoles
-		}
 	}
-
-	return cost
+	roomCal.Reserve(activity, room, block)
 }
 
 func abs(x int) int {
@@ -627,103 +484,39 @@ func abs(x int) int {
 	return x
 }
 
-// calculateTotalCostWithRooms calcula costo total incluyendo la consistencia de salas y separación de días
-func calculateTotalCostWithRooms(activities []domain.Activity, siblings map[string][]*domain.Activity, prereqPairs []PrereqPair) float64 {
-	cost := 0.0
-
-	// costo de espejo, sala y separación de días
+// calculateMirrorPenalty calcula la penalidad de espejo: 50 por cada cátedra hermana en otro slot y 20 por cada
+// una en otra sala. Es una métrica fija para comparar ejecuciones, no depende de los pesos de la función objetivo.
+func calculateMirrorPenalty(activities []domain.Activity, siblings map[string][]*domain.Activity) float64 {
+	penalty := 0.0
 	counted := make(map[string]bool)
+
 	for i := range activities {
 		a := &activities[i]
-		if a.SiblingGroupID == "" || a.Type != domain.CAT || counted[a.SiblingGroupID] {
+		if a.SiblingGroupID == "" || counted[a.SiblingGroupID] {
 			continue
 		}
 		counted[a.SiblingGroupID] = true
 
 		sibs := siblings[a.SiblingGroupID]
-
-		// filtrar solo catedra
-		var catSibs []*domain.Activity
-		for _, sib := range sibs {
-			if sib.Type == domain.CAT {
-				catSibs = append(catSibs, sib)
-			}
-		}
-
-		if len(catSibs) < 2 {
+		if len(sibs) < 2 {
 			continue
 		}
 
-		baseDay, baseSlot := blockToDaySlot(catSibs[0].Block)
-		baseRoom := catSibs[0].Room
-
-		for j := 1; j < len(catSibs); j++ {
-			day, slot := blockToDaySlot(catSibs[j].Block)
+		// Verificar si todos están en espejo
+		_, baseSlot := blockToDaySlot(sibs[0].Block)
+		baseRoom := sibs[0].Room
 
-			// espejo
+		for j := 1; j < len(sibs); j++ {
+			_, slot := blockToDaySlot(sibs[j].Block)
 			if slot != baseSlot {
-				cost += 50.0
-			}
-			// sala
-			if catSibs[j].Room != baseRoom {
-				cost += 30.0
-			}
-
-			// separación de días
-			daySeparation := abs(day - baseDay)
-			if len(catSibs) == 2 {
-				switch daySeparation {
-				case 3:
-					cost -= 20.0
-				case 2:
-					cost += 0.0
-				case 1:
-					cost += 25.0
-				case 0:
-					cost += 60.0
-				default:
-					cost += 10.0
-				}
-			} else if len(catSibs) >= 3 {
-				if daySeparation == 0 {
-					cost += 80.0
-				} else if daySeparation == 1 {
-					cost += 15.0
-				}
-			}
-		}
-
-		for _, cat := range catSibs {
-			catDay, _ := blockToDaySlot(cat.Block)
-			for _, sib := range sibs {
-				if sib.Type == domain.AY {
-					ayDay, _ := blockToDaySlot(sib.Block)
-					if catDay == ayDay {
-						cost += 35.0
-					}
-				}
+				penalty += 50.0
 			}
-		}
-	}
-
-	// Costo de AY no en miércoles
-	for i := range activities {
-		if activities[i].Type == domain.AY {
-			day, _ := blockToDaySlot(activities[i].Block)
-			if day != 2 {
-				cost += 10.0
+			if sibs[j].Room != baseRoom {
+				penalty += 20.0
 			}
 		}
 	}
-
-	// Bonus por prereqs en mismo bloque
-	for _, pair := range prereqPairs {
-		if pair.PrereqActivity.Block == pair.DepActivity.Block {
-			cost -= 15.0
-		}
-	}
-
-	return cost
+	return penalty
 }
 
 // calculateRoomConsistency calcula % de grupos de hermanos que comparten sala
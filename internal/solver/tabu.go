@@ -0,0 +1,234 @@
+package solver
+
+import (
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+)
+
+// TabuConfig contiene los parámetros de la búsqueda tabú.
+type TabuConfig struct {
+	Iterations   int  // Iteraciones (se aplica un movimiento por iteración)
+	Neighbors    int  // Movimientos candidatos válidos evaluados por iteración
+	Tenure       int  // Iteraciones que una actividad no puede volver al bloque o sala que dejó
+	Aspiration   bool // Aceptar un movimiento tabú si mejora el mejor costo encontrado
+	MaxNoImprove int  // Detener tras N iteraciones sin mejorar el mejor costo (0 = no detener)
+
+	Weights domain.ObjectiveWeights // Pesos de los términos de la función objetivo
+	Moves   MoveProbabilities       // Probabilidad relativa de cada tipo de movimiento (nil = DefaultMoveProbabilities)
+
+	Seed int64 // Semilla del generador aleatorio (0 = aleatoria)
+
+	CheckEvery int // Cada cuántas iteraciones comparar el costo incremental con el recálculo completo (0 = nunca)
+
+	Anchors map[string]Anchor // Horario publicado a perturbar lo menos posible, por código (nil = optimizar libremente)
+}
+
+// DefaultTabuConfig retorna configuración por defecto.
+func DefaultTabuConfig() TabuConfig {
+	return TabuConfig{
+		Iterations:   50000,
+		Neighbors:    50,
+		Tenure:       20,
+		Aspiration:   true,
+		MaxNoImprove: 10000,
+
+		Weights: domain.DefaultObjectiveWeights(),
+		Moves:   DefaultMoveProbabilities(),
+	}
+}
+
+// tabuKey identifica un atributo prohibido: una actividad volviendo a un bloque (room vacío) o a una sala (block -1)
+type tabuKey struct {
+	id    int
+	block int
+	room  string
+}
+
+// tabuCandidate es un movimiento evaluado en una iteración, con el destino final de cada actividad movida
+type tabuCandidate struct {
+	move       string
+	placements []placement
+	delta      float64
+	hardDelta  int
+}
+
+// TabuSearch optimiza la asignación de bloques y salas con búsqueda tabú. Recibe las mismas entradas que
+// SimulatedAnnealing y retorna las mismas métricas. En cada iteración evalúa una muestra de movimientos y aplica
+// el mejor que no sea tabú (aunque empeore); el horario final es el mejor encontrado.
+func TabuSearch(activities []domain.Activity, rooms []domain.Room, config TabuConfig, prerequisites map[string][]string, planLocations map[string]map[string]int, electives map[string]bool, constraints loader.RoomConstraints, teachers []domain.Teacher) SAResult {
+	st := newSearchState(activities, rooms, &config.Weights, config.Anchors, prerequisites, planLocations, electives, constraints, teachers)
+	obj := st.obj
+
+	rng := newRand(config.Seed)
+	if config.Moves == nil {
+		config.Moves = DefaultMoveProbabilities()
+	}
+	nb := newNeighborhood(obj, rooms, st.roomMap, constraints, rng)
+
+	tabu := make(map[tabuKey]int) // atributo -> última iteración en que sigue prohibido
+	isTabu := func(c *tabuCandidate, iteration int) bool {
+		for _, p := range c.placements {
+			a := p.activity
+			if p.block != a.Block && tabu[tabuKey{id: a.ID, block: p.block}] >= iteration {
+				return true
+			}
+			if p.block == a.Block && tabu[tabuKey{id: a.ID, block: -1, room: p.room}] >= iteration {
+				return true
+			}
+		}
+		return false
+	}
+
+	currentCost := st.initialCost
+	bestCost := currentCost
+	best := st.snapshot()
+	sinceBest := 0
+	iterations := 0
+	improvements := 0
+
+	for iterations < config.Iterations {
+		iterations++
+		if config.CheckEvery > 0 && iterations%config.CheckEvery == 0 {
+			obj.check(currentCost, iterations)
+		}
+
+		// Evaluar una muestra de movimientos y quedarse con el mejor admisible
+		var chosen *tabuCandidate
+		evaluated := 0
+		for attempt := 0; attempt < 4*config.Neighbors && evaluated < config.Neighbors; attempt++ {
+			activity := &activities[rng.Intn(len(activities))]
+			if activity.Block < 0 {
+				continue
+			}
+			move := config.Moves.pick(rng)
+			st.moveStats[move].Attempted++
+
+			c := st.tabuCandidate(nb, activity, move)
+			if c == nil {
+				continue
+			}
+			st.moveStats[move].Feasible++
+			evaluated++
+
+			if isTabu(c, iterations) && !(config.Aspiration && currentCost+c.delta < bestCost) {
+				continue
+			}
+			if chosen == nil || c.delta < chosen.delta {
+				chosen = c
+			}
+		}
+		if chosen == nil {
+			continue
+		}
+
+		// Aplicar el movimiento y, solo si se aplicó, prohibir que las actividades vuelvan a su bloque o sala anterior
+		previous := make([]placement, len(chosen.placements))
+		for i, p := range chosen.placements {
+			previous[i] = placement{activity: p.activity, block: p.activity.Block, room: p.activity.Room}
+		}
+		if len(chosen.placements) == 1 {
+			p := chosen.placements[0]
+			obj.apply(p.activity, p.block, p.room, chosen.hardDelta)
+		} else if _, _, ok := nb.tryPlacements(chosen.placements); !ok {
+			continue
+		}
+		for i, p := range chosen.placements {
+			prev := previous[i]
+			if p.block != prev.block {
+				tabu[tabuKey{id: prev.activity.ID, block: prev.block}] = iterations + config.Tenure
+			} else {
+				tabu[tabuKey{id: prev.activity.ID, block: -1, room: prev.room}] = iterations + config.Tenure
+			}
+		}
+
+		currentCost += chosen.delta
+		stats := st.moveStats[chosen.move]
+		stats.Accepted++
+		if chosen.delta < 0 {
+			improvements++
+			stats.Improving++
+		}
+
+		if currentCost < bestCost {
+			bestCost = currentCost
+			best = st.snapshot()
+			sinceBest = 0
+		} else {
+			sinceBest++
+			if config.MaxNoImprove > 0 && sinceBest >= config.MaxNoImprove {
+				break
+			}
+		}
+	}
+	if config.CheckEvery > 0 {
+		obj.check(currentCost, iterations)
+	}
+
+	// Volver al mejor horario encontrado
+	st.restore(best)
+	return st.result(iterations, improvements)
+}
+
+// tabuCandidate evalúa un movimiento sin dejarlo aplicado, o retorna nil si no es válido
+func (s *searchState) tabuCandidate(nb *neighborhood, activity *domain.Activity, move string) *tabuCandidate {
+	var placements []placement
+	switch move {
+	case MoveSwap:
+		placements = nb.swapMove(activity)
+	case MoveSibling:
+		placements = nb.siblingMove(activity)
+	case MoveKempe:
+		placements = nb.kempeMove(activity)
+	default:
+		newBlock, newRoom, ok := s.simpleMove(activity, move, nb.rng)
+		if !ok {
+			return nil
+		}
+		delta, hardDelta := s.obj.moveDelta(activity, newBlock, newRoom)
+		return &tabuCandidate{
+			move:       move,
+			placements: []placement{{activity: activity, block: newBlock, room: newRoom}},
+			delta:      delta,
+			hardDelta:  hardDelta,
+		}
+	}
+
+	// Los movimientos compuestos se evalúan aplicándolos y deshaciéndolos; se guardan las salas elegidas
+	// para que aplicarlo después dé exactamente el mismo horario
+	delta, undo, ok := nb.tryPlacements(placements)
+	if !ok {
+		return nil
+	}
+	final := make([]placement, len(undo))
+	for i, u := range undo {
+		final[i] = placement{activity: u.activity, block: u.activity.Block, room: u.activity.Room}
+	}
+	nb.undoPlacements(undo)
+	return &tabuCandidate{move: move, placements: final, delta: delta}
+}
+
+// snapshot guarda el bloque y la sala de cada actividad
+func (s *searchState) snapshot() []placement {
+	snap := make([]placement, len(s.activities))
+	for i := range s.activities {
+		a := &s.activities[i]
+		snap[i] = placement{activity: a, block: a.Block, room: a.Room}
+	}
+	return snap
+}
+
+// restore vuelve el horario a un snapshot, reconstruyendo los índices de ocupación y el conteo de violaciones
+func (s *searchState) restore(snap []placement) {
+	for _, p := range snap {
+		if p.activity.Block >= 0 {
+			removeFromOccupancy(p.activity, p.activity.Block, p.activity.Room, s.blockOcc, s.roomCal)
+		}
+	}
+	for _, p := range snap {
+		p.activity.Block, p.activity.Room = p.block, p.room
+		if p.block >= 0 {
+			addToOccupancy(p.activity, p.block, p.room, s.blockOcc, s.roomCal)
+		}
+	}
+	s.obj.hard = countTotalHardViolations(s.activities, s.blockOcc, s.cliqueConflicts, s.teacherIndex, s.courseMajors)
+}
@@ -0,0 +1,44 @@
+package solver
+
+import "timetabling-UDP/internal/domain"
+
+// buildTeacherIndex crea un índice de profesores por nombre, que es como los referencian las actividades
+func buildTeacherIndex(teachers []domain.Teacher) map[string]*domain.Teacher {
+	index := make(map[string]*domain.Teacher)
+	for i := range teachers {
+		index[teachers[i].Name] = &teachers[i]
+	}
+	return index
+}
+
+// isTeacherBusy verifica si algún profesor de la actividad está ocupado en alguno de los bloques que ocuparía
+func isTeacherBusy(activity *domain.Activity, block int, teacherIndex map[string]*domain.Teacher) bool {
+	duration := activity.Span()
+	for _, name := range activity.TeacherNames {
+		if t, ok := teacherIndex[name]; ok && !t.IsAvailable(block, duration) {
+			return true
+		}
+	}
+	return false
+}
+
+// teacherPreferenceCost calcula el costo de preferencias de los profesores si la actividad comienza en block.
+// Cada nivel de preferencia vale weight: los bloques no deseados suman costo y los preferidos lo restan.
+func teacherPreferenceCost(activity *domain.Activity, block int, teacherIndex map[string]*domain.Teacher, weight float64) float64 {
+	if weight == 0 || block < 0 {
+		return 0
+	}
+	duration := activity.Span()
+
+	cost := 0.0
+	for _, name := range activity.TeacherNames {
+		t, ok := teacherIndex[name]
+		if !ok {
+			continue
+		}
+		for d := 0; d < duration; d++ {
+			cost -= float64(t.Availability.Preference(block+d)) * weight
+		}
+	}
+	return cost
+}
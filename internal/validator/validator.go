@@ -0,0 +1,298 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"timetabling-UDP/internal/domain"
+	"timetabling-UDP/internal/loader"
+	"timetabling-UDP/internal/utils"
+)
+
+// Kind identifica la regla dura que el horario no cumple
+type Kind string
+
+const (
+	KindUnscheduled        Kind = "SIN_BLOQUE"             // la actividad no tiene bloque
+	KindNoRoom             Kind = "SIN_SALA"               // la actividad no tiene sala
+	KindUnknownRoom        Kind = "SALA_DESCONOCIDA"       // la sala no está en rooms.csv
+	KindOutsideDay         Kind = "FUERA_DEL_DIA"          // la actividad no termina el mismo día o usa slots fuera de la grilla
+	KindProtectedBlock     Kind = "HORARIO_PROTEGIDO"      // ocupa un periodo bloqueado para toda la institución
+	KindBlockedPeriod      Kind = "PERIODO_BLOQUEADO"      // ocupa un periodo bloqueado para su carrera
+	KindTeacherUnavailable Kind = "PROFESOR_NO_DISPONIBLE" // fuera de la disponibilidad del profesor
+	KindTeacherClash       Kind = "PROFESOR_OCUPADO"       // el profesor dicta dos actividades a la vez
+	KindSectionClash       Kind = "SECCION"                // la misma sección tiene dos actividades a la vez
+	KindSemesterClash      Kind = "SEMESTRE"               // dos cursos de la misma clique de semestre a la vez
+	KindRoomClash          Kind = "SALA_OCUPADA"           // RC3: dos actividades en la misma sala a la vez
+	KindCapacity           Kind = "CAPACIDAD"              // RC4: la sala no tiene capacidad para los estudiantes
+	KindRoomType           Kind = "TIPO_SALA"              // RC5: laboratorio fuera de laboratorio o clase en laboratorio
+	KindRoomNotAllowed     Kind = "SALA_NO_PERMITIDA"      // RC6: sala fuera de las permitidas por rooms_constraints.json
+)
+
+// Kinds lista las reglas en el orden en que se reportan
+var Kinds = []Kind{
+	KindUnscheduled, KindNoRoom, KindUnknownRoom, KindOutsideDay, KindProtectedBlock, KindBlockedPeriod,
+	KindTeacherUnavailable, KindTeacherClash, KindSectionClash, KindSemesterClash,
+	KindRoomClash, KindCapacity, KindRoomType, KindRoomNotAllowed,
+}
+
+// Violation es una regla dura que una actividad (o un par de actividades) no cumple
+type Violation struct {
+	Kind     Kind
+	Activity string // código de la actividad
+	Other    string // actividad con la que choca (solo choques entre actividades)
+	Block    int
+	Room     string
+	Detail   string
+}
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("[%s] %s", v.Kind, v.Activity)
+	if v.Block >= 0 {
+		s += " | " + domain.BlockLabel(v.Block)
+	}
+	if v.Room != "" {
+		s += " | " + v.Room
+	}
+	if v.Other != "" {
+		s += " | con " + v.Other
+	}
+	if v.Detail != "" {
+		s += " | " + v.Detail
+	}
+	return s
+}
+
+// Rules son los datos de entrada contra los que se valida el horario. La grilla activa ya debe estar configurada.
+type Rules struct {
+	Rooms         []domain.Room
+	Teachers      []domain.Teacher
+	Constraints   loader.RoomConstraints
+	PlanLocations map[string]map[string]int // CourseCode -> Major -> Semester
+	Electives     map[string]bool
+}
+
+// Validate revisa un horario terminado contra todas las reglas duras y retorna las violaciones, ordenadas por
+// regla y actividad. No usa los índices del solver: cada regla se vuelve a calcular desde las entradas.
+func Validate(activities []domain.Activity, r Rules) []Violation {
+	v := &checker{
+		rules:     r,
+		rooms:     make(map[string]domain.Room, len(r.Rooms)),
+		teachers:  make(map[string]*domain.Teacher, len(r.Teachers)),
+		semesters: semesterCliques(activities, r.PlanLocations, r.Electives),
+	}
+	for _, room := range r.Rooms {
+		v.rooms[room.Code] = room
+	}
+	for i := range r.Teachers {
+		v.teachers[r.Teachers[i].Name] = &r.Teachers[i]
+	}
+
+	for i := range activities {
+		v.checkActivity(&activities[i])
+	}
+	v.checkPairs(activities)
+	v.checkRooms(activities)
+
+	order := make(map[Kind]int, len(Kinds))
+	for i, k := range Kinds {
+		order[k] = i
+	}
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		a, b := v.violations[i], v.violations[j]
+		if a.Kind != b.Kind {
+			return order[a.Kind] < order[b.Kind]
+		}
+		return a.Activity < b.Activity
+	})
+	return v.violations
+}
+
+// CountByKind cuenta las violaciones de cada regla
+func CountByKind(violations []Violation) map[Kind]int {
+	counts := make(map[Kind]int)
+	for _, v := range violations {
+		counts[v.Kind]++
+	}
+	return counts
+}
+
+// checker acumula las violaciones con los índices de las entradas
+type checker struct {
+	rules      Rules
+	rooms      map[string]domain.Room
+	teachers   map[string]*domain.Teacher
+	semesters  map[string][]string // curso -> cliques de semestre ("carrera:semestre") a las que pertenece
+	violations []Violation
+}
+
+func (v *checker) add(kind Kind, a *domain.Activity, other, detail string) {
+	v.violations = append(v.violations, Violation{Kind: kind, Activity: a.Code, Other: other, Block: a.Block, Room: a.Room, Detail: detail})
+}
+
+// checkActivity revisa las reglas de una actividad sola: bloque, grilla, periodos bloqueados, profesores y sala
+func (v *checker) checkActivity(a *domain.Activity) {
+	if a.Block < 0 {
+		v.add(KindUnscheduled, a, "", "")
+		return
+	}
+	duration := a.Span()
+
+	if !domain.FitsInDay(a.Block, duration) {
+		v.add(KindOutsideDay, a, "", fmt.Sprintf("%d bloque(s)", duration))
+	}
+	if domain.OccupiesProtectedBlock(a.Block, duration) {
+		v.add(KindProtectedBlock, a, "", "")
+	} else if majors := v.majors(a.CourseCode); domain.OccupiesBlockedPeriod(a.Block, duration, majors) {
+		v.add(KindBlockedPeriod, a, "", "")
+	}
+	for _, name := range a.TeacherNames {
+		if t, ok := v.teachers[name]; ok && !t.IsAvailable(a.Block, duration) {
+			v.add(KindTeacherUnavailable, a, "", name)
+		}
+	}
+
+	if a.Room == "" {
+		v.add(KindNoRoom, a, "", "")
+		return
+	}
+	room, ok := v.rooms[a.Room]
+	if !ok {
+		v.add(KindUnknownRoom, a, "", "")
+		return
+	}
+	if a.Students > room.Capacity {
+		v.add(KindCapacity, a, "", fmt.Sprintf("%d estudiantes, capacidad %d", a.Students, room.Capacity))
+	}
+	if allowed := v.rules.Constraints.GetAllowedRooms(a.CourseCode, string(a.Type)); allowed != nil {
+		if !contains(allowed, room.Code) {
+			v.add(KindRoomNotAllowed, a, "", "")
+		}
+	} else if (a.Type == domain.LAB) != (room.Type == domain.RoomLab) {
+		v.add(KindRoomType, a, "", fmt.Sprintf("%s en sala tipo %s", a.Type, room.Type))
+	}
+}
+
+// checkPairs revisa los choques entre actividades que se traslapan en el tiempo: profesor, sección y clique de
+// semestre. Un par que rompe varias reglas aparece una vez por regla.
+func (v *checker) checkPairs(activities []domain.Activity) {
+	byBlock := make(map[int][]*domain.Activity)
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 {
+			continue
+		}
+		for b := a.Block; b < a.Block+a.Span(); b++ {
+			byBlock[b] = append(byBlock[b], a)
+		}
+	}
+
+	seen := make(map[[2]*domain.Activity]bool)
+	blocks := make([]int, 0, len(byBlock))
+	for b := range byBlock {
+		blocks = append(blocks, b)
+	}
+	sort.Ints(blocks)
+	for _, b := range blocks {
+		list := byBlock[b]
+		for i, a := range list {
+			for _, other := range list[i+1:] {
+				first, second := a, other
+				if second.Code < first.Code {
+					first, second = second, first
+				}
+				pair := [2]*domain.Activity{first, second}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+
+				if first.SharesTeacher(second) {
+					v.add(KindTeacherClash, first, second.Code, "")
+				}
+				if first.SharesSection(second) {
+					v.add(KindSectionClash, first, second.Code, "")
+				}
+				if first.CourseCode != second.CourseCode && v.sameSemester(first.CourseCode, second.CourseCode) {
+					v.add(KindSemesterClash, first, second.Code, "")
+				}
+			}
+		}
+	}
+}
+
+// checkRooms revisa que ninguna sala tenga dos actividades en el mismo bloque, considerando la duración (RC3)
+func (v *checker) checkRooms(activities []domain.Activity) {
+	cal := domain.NewRoomCalendar()
+	seen := make(map[[2]*domain.Activity]bool)
+	for i := range activities {
+		a := &activities[i]
+		if a.Block < 0 || a.Room == "" {
+			continue
+		}
+		for b := a.Block; b < a.Block+a.Span(); b++ {
+			other := cal.At(a.Room, b)
+			if other == nil || seen[[2]*domain.Activity{other, a}] {
+				continue
+			}
+			seen[[2]*domain.Activity{other, a}] = true
+			v.add(KindRoomClash, a, other.Code, "")
+		}
+		cal.Reserve(a, a.Room, a.Block)
+	}
+}
+
+// majors retorna las carreras del curso según PlanLocation
+func (v *checker) majors(courseCode string) []string {
+	var majors []string
+	for major := range v.rules.PlanLocations[courseCode] {
+		majors = append(majors, major)
+	}
+	sort.Strings(majors)
+	return majors
+}
+
+// sameSemester verifica si dos cursos pertenecen a la misma clique de semestre
+func (v *checker) sameSemester(c1, c2 string) bool {
+	for _, k1 := range v.semesters[c1] {
+		for _, k2 := range v.semesters[c2] {
+			if k1 == k2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// semesterCliques asigna a cada curso las cliques de semestre a las que pertenece: los cursos no electivos con una
+// sola sección (o secciones fusionadas) de la misma carrera y semestre no pueden dictarse a la vez
+func semesterCliques(activities []domain.Activity, planLocations map[string]map[string]int, electives map[string]bool) map[string][]string {
+	sectionGroups := make(map[string]map[string]bool)
+	for i := range activities {
+		a := &activities[i]
+		if sectionGroups[a.CourseCode] == nil {
+			sectionGroups[a.CourseCode] = make(map[string]bool)
+		}
+		sectionGroups[a.CourseCode][utils.SectionGroupKey(a.Sections)] = true
+	}
+
+	cliques := make(map[string][]string)
+	for code, groups := range sectionGroups {
+		if len(groups) != 1 || electives[code] {
+			continue
+		}
+		for major, semester := range planLocations[code] {
+			cliques[code] = append(cliques[code], fmt.Sprintf("%s:%d", major, semester))
+		}
+	}
+	return cliques
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -13,8 +13,10 @@ const state = {
     view: 'grid'
 };
 
-const DAYS = ['Lunes', 'Martes', 'Miércoles', 'Jueves', 'Viernes'];
-const TIME_SLOTS = [
+// Grilla por defecto, se reemplaza con la grilla incluida en schedule.json
+let DAYS = ['Lunes', 'Martes', 'Miércoles', 'Jueves', 'Viernes'];
+let DAY_SLOTS = [7, 7, 7, 7, 7];
+let TIME_SLOTS = [
     { block: 0, time: '08:30-09:50' },
     { block: 1, time: '10:00-11:20' },
     { block: 2, time: '11:30-12:50' },
@@ -23,8 +25,9 @@ const TIME_SLOTS = [
     { block: 5, time: '16:00-17:20' },
     { block: 6, time: '17:25-18:45' }
 ];
-const BLOCKS_PER_DAY = 7;
-const PROTECTED_BLOCK = 16; // Miércoles 11:30-12:50
+let BLOCKS_PER_DAY = 7;
+let PROTECTED_BLOCKS = [16]; // Miércoles 11:30-12:50
+let BLOCKED_PERIODS = [{ name: 'Protegido', blocks: [16], majors: [] }];
 
 const elements = {
     filterDay: document.getElementById('filter-day'),
@@ -67,6 +70,7 @@ async function loadScheduleData() {
         throw new Error('Failed to load schedule.json');
     }
     state.scheduleData = await response.json();
+    applyGrid(state.scheduleData.grid);
 
     state.allActivities = [];
     for (const day of state.scheduleData.schedule) {
@@ -83,6 +87,22 @@ async function loadScheduleData() {
     state.filteredActivities = [...state.allActivities];
 }
 
+function applyGrid(grid) {
+    if (!grid || !grid.days || !grid.slots) {
+        return;
+    }
+    DAYS = grid.days;
+    BLOCKS_PER_DAY = grid.blocks_per_day;
+    DAY_SLOTS = grid.day_slots || grid.days.map(() => grid.blocks_per_day);
+    TIME_SLOTS = grid.slots.map((slot, index) => ({ block: index, time: slot.label }));
+    PROTECTED_BLOCKS = grid.protected_blocks || [];
+    BLOCKED_PERIODS = grid.blocked_periods || [];
+}
+
+function blockedPeriodsAt(blockNum) {
+    return BLOCKED_PERIODS.filter(p => p.blocks.includes(blockNum));
+}
+
 function populateFilters() {
     DAYS.forEach(day => {
         const option = document.createElement('option');
@@ -243,6 +263,7 @@ function renderCurrentView() {
 function renderGridView() {
     const grid = elements.scheduleGrid;
     grid.innerHTML = '';
+    grid.style.gridTemplateColumns = `80px repeat(${DAYS.length}, 1fr)`;
 
     grid.appendChild(createGridHeader('Hora'));
     DAYS.forEach(day => grid.appendChild(createGridHeader(day)));
@@ -261,9 +282,22 @@ function renderGridView() {
             const cell = document.createElement('div');
             cell.className = 'grid-cell';
 
-            if (blockNum === PROTECTED_BLOCK) {
+            const blocked = blockedPeriodsAt(blockNum);
+            const institutional = blocked.find(p => !p.majors || p.majors.length === 0);
+
+            if (slotIndex >= DAY_SLOTS[dayIndex]) {
+                cell.classList.add('unavailable');
+            } else if (institutional || PROTECTED_BLOCKS.includes(blockNum)) {
                 cell.classList.add('protected');
+                cell.dataset.label = institutional ? institutional.name : 'Protegido';
             } else {
+                blocked.forEach(p => {
+                    const tag = document.createElement('div');
+                    tag.className = 'blocked-tag';
+                    tag.textContent = `🔒 ${p.name} (${p.majors.join(', ')})`;
+                    cell.appendChild(tag);
+                });
+
                 const cellActivities = state.filteredActivities.filter(a =>
                     a.dayName === day && a.block === blockNum
                 );